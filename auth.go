@@ -0,0 +1,42 @@
+package room
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+const bearerPrefix = "Bearer "
+
+// SetBasicAuth sends "Authorization: Basic <base64(username:password)>" with the request.
+func (r *Request) SetBasicAuth(username, password string) *Request {
+	r.authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+
+	return r
+}
+
+func WithBasicAuth(username, password string) OptionRequest {
+	return func(request *Request) {
+		request.SetBasicAuth(username, password)
+	}
+}
+
+// SetBearerToken sends "Authorization: Bearer <token>". A token that already carries the prefix is not prefixed twice.
+func (r *Request) SetBearerToken(token string) *Request {
+	r.authorization = bearer(token)
+
+	return r
+}
+
+func bearer(token string) string {
+	if len(token) >= len(bearerPrefix) && strings.EqualFold(token[:len(bearerPrefix)], bearerPrefix) {
+		token = token[len(bearerPrefix):]
+	}
+
+	return bearerPrefix + token
+}
+
+func WithBearerToken(token string) OptionRequest {
+	return func(request *Request) {
+		request.SetBearerToken(token)
+	}
+}
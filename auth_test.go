@@ -0,0 +1,58 @@
+package room
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequest_SendWithBasicAuth(t *testing.T) {
+	tests := []struct {
+		username string
+		password string
+	}{
+		{"user", "secret"},
+		{"user", "pass:with:colons"},
+		{"kullanıcı", "şifre"},
+	}
+
+	for _, test := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			username, password, ok := r.BasicAuth()
+			if !ok || username != test.username || password != test.password {
+				t.Errorf("server received (%q, %q, %t), expected (%q, %q, true)", username, password, ok, test.username, test.password)
+			}
+			if r.Header.Get("X-Custom") != "value" {
+				t.Error("WithBasicAuth() clobbered an unrelated header")
+			}
+		}))
+
+		_, err := NewRequest(server.URL,
+			WithHeader(NewHeader().Add("X-Custom", "value")),
+			WithBasicAuth(test.username, test.password),
+		).Send()
+		if err != nil {
+			t.Errorf("Send() returned unexpected error: %v", err)
+		}
+
+		server.Close()
+	}
+}
+
+func TestRequest_SetBearerToken(t *testing.T) {
+	tests := []struct {
+		token    string
+		expected string
+	}{
+		{"abc", "Bearer abc"},
+		{"Bearer abc", "Bearer abc"},
+		{"bearer abc", "Bearer abc"},
+	}
+
+	for _, test := range tests {
+		r := NewRequest("http://localhost", WithBearerToken(test.token))
+		if r.authorization != test.expected {
+			t.Errorf("WithBearerToken(%q) set %q, expected %q", test.token, r.authorization, test.expected)
+		}
+	}
+}
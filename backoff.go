@@ -0,0 +1,38 @@
+package room
+
+import (
+	"math/rand"
+	"time"
+)
+
+// ConstantBackoff waits d before every attempt.
+func ConstantBackoff(d time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		return d
+	}
+}
+
+// LinearBackoff waits step, 2*step, 3*step and so on.
+func LinearBackoff(step time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		return step * time.Duration(attempt)
+	}
+}
+
+// ExponentialBackoff doubles base on every attempt up to max.
+// With jitter it returns a random delay between zero and that value ("full jitter").
+func ExponentialBackoff(base, max time.Duration, jitter bool) BackoffFunc {
+	return func(attempt int) time.Duration {
+		delay := max
+
+		if shift := attempt - 1; shift < 63 && base <= max>>shift {
+			delay = base << shift
+		}
+
+		if jitter && delay > 0 {
+			return time.Duration(rand.Int63n(int64(delay) + 1))
+		}
+
+		return delay
+	}
+}
@@ -0,0 +1,51 @@
+package room
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConstantBackoff(t *testing.T) {
+	backoff := ConstantBackoff(time.Second)
+	for attempt := 1; attempt <= 3; attempt++ {
+		if result := backoff(attempt); result != time.Second {
+			t.Errorf("ConstantBackoff() returned %v for attempt %d, expected %v", result, attempt, time.Second)
+		}
+	}
+}
+
+func TestLinearBackoff(t *testing.T) {
+	backoff := LinearBackoff(time.Second)
+	if result := backoff(3); result != 3*time.Second {
+		t.Errorf("LinearBackoff() returned %v for attempt 3, expected %v", result, 3*time.Second)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(100*time.Millisecond, time.Second, false)
+	tests := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{4, 800 * time.Millisecond},
+		{5, time.Second},
+		{100, time.Second},
+	}
+
+	for _, test := range tests {
+		if result := backoff(test.attempt); result != test.expected {
+			t.Errorf("ExponentialBackoff() returned %v for attempt %d, expected %v", result, test.attempt, test.expected)
+		}
+	}
+}
+
+func TestExponentialBackoffWithJitter(t *testing.T) {
+	backoff := ExponentialBackoff(100*time.Millisecond, time.Second, true)
+	for i := 0; i < 100; i++ {
+		if result := backoff(3); result < 0 || result > 400*time.Millisecond {
+			t.Fatalf("ExponentialBackoff() with jitter returned %v, expected between 0 and %v", result, 400*time.Millisecond)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package room
+
+import "sync"
+
+// Result holds the outcome of one request sent by SendAll.
+type Result struct {
+	Response Response
+	Err      error
+}
+
+// SendAll sends reqs with at most concurrency of them in flight, all at once when concurrency is not positive,
+// and returns their results in the order of reqs. Each request is sent with Send, so its own context and timeout apply.
+// A request whose context is already done when its turn comes is not sent, its result carries the context error,
+// so cancelling a parent context shared with WithContext stops the batch early.
+func SendAll(reqs []*Request, concurrency int) []Result {
+	results := make([]Result, len(reqs))
+
+	if concurrency <= 0 || concurrency > len(reqs) {
+		concurrency = len(reqs)
+	}
+
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for worker := 0; worker < concurrency; worker++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				results[i] = reqs[i].sendResult()
+			}
+		}()
+	}
+
+	for i := range reqs {
+		indexes <- i
+	}
+
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+func (r *Request) sendResult() Result {
+	if r.ctx != nil && r.ctx.Err() != nil {
+		response, err := NewErrorResponse(nil, r.ctx.Err())
+
+		return Result{Response: response, Err: err}
+	}
+
+	response, err := r.Send()
+
+	return Result{Response: response, Err: err}
+}
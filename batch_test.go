@@ -0,0 +1,76 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSendAll(t *testing.T) {
+	var inFlight, peak atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		for {
+			if previous := peak.Load(); current <= previous || peak.CompareAndSwap(previous, current) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+
+	paths := []string{"/a", "/b", "/c", "/d", "/e", "/f"}
+	reqs := make([]*Request, len(paths))
+
+	for i, path := range paths {
+		reqs[i] = NewRequest(server.URL + path)
+	}
+
+	results := SendAll(reqs, 2)
+
+	for i, result := range results {
+		if result.Err != nil || string(result.Response.Data) != paths[i] {
+			t.Errorf("SendAll() result %d returned (%s, %v), expected %s", i, result.Response.Data, result.Err, paths[i])
+		}
+	}
+	if peak.Load() > 2 {
+		t.Errorf("SendAll() ran %d requests at once, expected at most 2", peak.Load())
+	}
+}
+
+func TestSendAll_Canceled(t *testing.T) {
+	var sent atomic.Int32
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent.Add(1)
+		cancel()
+	}))
+	defer server.Close()
+
+	reqs := []*Request{
+		NewRequest(server.URL, WithContext(ctx)),
+		NewRequest(server.URL, WithContext(ctx)),
+		NewRequest(server.URL, WithContext(ctx)),
+	}
+
+	results := SendAll(reqs, 1)
+
+	if sent.Load() != 1 {
+		t.Errorf("SendAll() sent %d requests, expected to stop after the parent context was cancelled", sent.Load())
+	}
+	if !errors.Is(results[2].Err, context.Canceled) {
+		t.Errorf("SendAll() returned %v for a request left unsent, expected context.Canceled", results[2].Err)
+	}
+}
@@ -3,13 +3,21 @@ package room
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/google/go-querystring/query"
+	"io"
 	"mime/multipart"
+	"net/http"
 	"net/url"
+	"os"
+	"sync"
 )
 
+// IBodyParser produces the request body. Parse is called once per attempt, so retries get a fresh reader,
+// and ContentType is read after Parse so it can depend on what Parse produced (e.g. a multipart boundary).
 type IBodyParser interface {
-	Parse() *bytes.Buffer
+	Parse() (io.Reader, error)
 	ContentType() string
 }
 
@@ -17,16 +25,14 @@ type JsonBody struct {
 	v any
 }
 
-func (f *JsonBody) Parse() *bytes.Buffer {
+func (f *JsonBody) Parse() (io.Reader, error) {
 	var buf bytes.Buffer
 
-	err := json.NewEncoder(&buf).Encode(f.v)
-
-	if err != nil {
-		panic(err)
+	if err := json.NewEncoder(&buf).Encode(f.v); err != nil {
+		return nil, err
 	}
 
-	return &buf
+	return &buf, nil
 }
 
 func (f *JsonBody) ContentType() string {
@@ -37,6 +43,11 @@ func NewJsonBodyParser(v any) IBodyParser {
 	return &JsonBody{v}
 }
 
+// JSONBody encodes v as an application/json request body.
+func JSONBody(v any) IBodyParser {
+	return NewJsonBodyParser(v)
+}
+
 func NewFormURLEncodedBodyParser(v any) IBodyParser {
 	return &FormURLEncodedBody{v}
 }
@@ -45,23 +56,42 @@ type FormURLEncodedBody struct {
 	v any
 }
 
+// FormBody encodes values as an application/x-www-form-urlencoded request body, repeated keys included.
+func FormBody(values url.Values) IBodyParser {
+	return NewFormURLEncodedBodyParser(values)
+}
+
 func (f *FormURLEncodedBody) ContentType() string {
-	return "application/x-www-form-urlencoded"
+	return headerValueFormEncoded
 }
 
-func (f *FormURLEncodedBody) Parse() *bytes.Buffer {
+func (f *FormURLEncodedBody) Parse() (io.Reader, error) {
 	values := url.Values{}
 
 	switch f.v.(type) {
+	case url.Values:
+		values = f.v.(url.Values)
+	case map[string][]string:
+		values = f.v.(map[string][]string)
 	case map[string]any:
 		for key, value := range f.v.(map[string]any) {
-			values.Add(key, value.(string))
+			text, ok := value.(string)
+
+			if !ok {
+				return nil, fmt.Errorf("encode form body: field %s is a %T, expected a string", key, value)
+			}
+
+			values.Add(key, text)
 		}
 	default:
-		values, _ = query.Values(f.v)
+		var err error
+
+		if values, err = query.Values(f.v); err != nil {
+			return nil, fmt.Errorf("encode form body: %w", err)
+		}
 	}
 
-	return bytes.NewBufferString(values.Encode())
+	return bytes.NewBufferString(values.Encode()), nil
 }
 
 // MultipartFormDataBody handles multipart/form-data encoding
@@ -74,7 +104,7 @@ func (f *MultipartFormDataBody) ContentType() string {
 	return f.contentType
 }
 
-func (f *MultipartFormDataBody) Parse() *bytes.Buffer {
+func (f *MultipartFormDataBody) Parse() (io.Reader, error) {
 	var body bytes.Buffer
 	writer := multipart.NewWriter(&body)
 
@@ -88,7 +118,7 @@ func (f *MultipartFormDataBody) Parse() *bytes.Buffer {
 
 	f.contentType = writer.FormDataContentType()
 
-	return &body
+	return &body, nil
 }
 
 func NewMultipartFormDataBodyParser(v any) IBodyParser {
@@ -111,8 +141,306 @@ func NewMultipartFormDataBodyParser(v any) IBodyParser {
 	}
 }
 
+// bodyLength returns the remaining length of readers whose size is known upfront, -1 otherwise.
+func bodyLength(body io.Reader) int64 {
+	switch body := body.(type) {
+	case interface{ Len() int }:
+		return int64(body.Len())
+	case *os.File:
+		info, err := body.Stat()
+
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+
+		offset, err := body.Seek(0, io.SeekCurrent)
+
+		if err != nil {
+			return -1
+		}
+
+		return info.Size() - offset
+	}
+
+	return -1
+}
+
+// ErrBodyNotReplayable is returned when a reader body read past MaxBodyReplaySize has to be sent again.
+var ErrBodyNotReplayable = errors.New("request body exceeds the replay buffer and cannot be sent again")
+
+// MaxBodyReplaySize caps the memory a ReaderBody that cannot seek keeps to be replayed.
+var MaxBodyReplaySize = 10 << 20
+
+type readerBody struct {
+	mu          sync.Mutex
+	reader      io.Reader
+	contentType string
+	parsed      bool
+	offset      int64
+	replay      *bytes.Buffer
+	drained     bool
+	exceeded    bool
+}
+
+// ReaderBody sends r as-is with the given content type. To send it again on a retry or a redirect, an io.Seeker is rewound
+// to the offset it had when first sent, any other reader is kept in memory as it is read so it can be replayed.
+// Once more than MaxBodyReplaySize is read the memory is released, sending it again failing with ErrBodyNotReplayable.
+func ReaderBody(r io.Reader, contentType string) IBodyParser {
+	return &readerBody{reader: r, contentType: contentType}
+}
+
+func (f *readerBody) Parse() (io.Reader, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.parsed {
+		f.parsed = true
+
+		if seeker, ok := f.reader.(io.Seeker); ok {
+			// A reader failing to report its offset, like an *os.File opened on a pipe, is replayed from memory.
+			if offset, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+				f.offset = offset
+
+				return f.reader, nil
+			}
+		}
+
+		f.replay = new(bytes.Buffer)
+
+		return f.replayReader(recordingReader{f}, 0), nil
+	}
+
+	if f.exceeded {
+		return nil, ErrBodyNotReplayable
+	}
+
+	if f.replay == nil {
+		if _, err := f.reader.(io.Seeker).Seek(f.offset, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("rewind request body: %w", err)
+		}
+
+		return f.reader, nil
+	}
+
+	replayed := bytes.NewReader(f.replay.Bytes())
+
+	if f.drained {
+		return f.replayReader(replayed, replayed.Len()), nil
+	}
+
+	// What the previous attempts did not read is still read from the source, and recorded for the next ones.
+	return f.replayReader(io.MultiReader(replayed, recordingReader{f}), replayed.Len()), nil
+}
+
+// replayReader closes the source along with the body, as the transport closes it once sent,
+// and keeps the length of a source reporting it, a bytes.Buffer for instance, known for the Content-Length.
+func (f *readerBody) replayReader(reader io.Reader, replayed int) io.Reader {
+	closing := closingReader{reader: reader, source: f.reader}
+
+	if f.drained {
+		return &sizedReader{closingReader: closing, remaining: replayed}
+	}
+
+	if source, ok := f.reader.(interface{ Len() int }); ok {
+		return &sizedReader{closingReader: closing, remaining: replayed + source.Len()}
+	}
+
+	return closing
+}
+
+// recordingReader reads the source of a readerBody, recording what it read for the next attempts.
+type recordingReader struct {
+	f *readerBody
+}
+
+func (r recordingReader) Read(p []byte) (int, error) {
+	n, err := r.f.reader.Read(p)
+
+	r.f.mu.Lock()
+	if !r.f.exceeded {
+		if r.f.replay.Len()+n > MaxBodyReplaySize {
+			r.f.replay, r.f.exceeded = nil, true
+		} else {
+			r.f.replay.Write(p[:n])
+		}
+	}
+	r.f.drained = r.f.drained || err == io.EOF
+	r.f.mu.Unlock()
+
+	return n, err
+}
+
+type closingReader struct {
+	reader io.Reader
+	source io.Reader
+}
+
+func (r closingReader) Read(p []byte) (int, error) { return r.reader.Read(p) }
+
+func (r closingReader) Close() error {
+	if closer, ok := r.source.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+type sizedReader struct {
+	closingReader
+	remaining int
+}
+
+func (r *sizedReader) Read(p []byte) (int, error) {
+	n, err := r.closingReader.Read(p)
+	r.remaining -= n
+
+	return n, err
+}
+
+func (r *sizedReader) Len() int { return max(r.remaining, 0) }
+
+func (f *readerBody) ContentType() string { return f.contentType }
+
+// sharedSourceBody is implemented by bodies whose successive Parse calls read one source, so the readers they return
+// must be read one after the other.
+type sharedSourceBody interface {
+	sharesSource() bool
+}
+
+func (f *readerBody) sharesSource() bool { return true }
+
+type autoDetectBody struct {
+	mu     sync.Mutex
+	source io.Reader
+	body   IBodyParser
+	err    error
+}
+
+// ReaderBodyAutoDetect sends r as a ReaderBody whose content type is sniffed from its first 512 bytes with
+// http.DetectContentType, application/octet-stream when it is empty or inconclusive. The sniffed bytes are read
+// before the request is sent and put back in front of the stream, or an io.Seeker is rewound.
+func ReaderBodyAutoDetect(r io.Reader) IBodyParser {
+	return &autoDetectBody{source: r}
+}
+
+func (f *autoDetectBody) Parse() (io.Reader, error) {
+	body, err := f.sniff()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return body.Parse()
+}
+
+func (f *autoDetectBody) ContentType() string {
+	body, err := f.sniff()
+
+	if err != nil {
+		return ""
+	}
+
+	return body.ContentType()
+}
+
+func (f *autoDetectBody) sharesSource() bool { return true }
+
+func (f *autoDetectBody) sniff() (IBodyParser, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.body != nil || f.err != nil {
+		return f.body, f.err
+	}
+
+	head := make([]byte, sniffSize)
+	n, err := io.ReadFull(f.source, head)
+
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		f.err = fmt.Errorf("sniff request body: %w", err)
+
+		return nil, f.err
+	}
+
+	head = head[:n]
+	contentType := headerValueOctetStream
+
+	if n > 0 {
+		contentType = http.DetectContentType(head)
+	}
+
+	if seeker, ok := f.source.(io.Seeker); ok {
+		if _, err = seeker.Seek(int64(-n), io.SeekCurrent); err == nil {
+			f.body = ReaderBody(f.source, contentType)
+
+			return f.body, nil
+		}
+	}
+
+	prefixed := &prefixReader{Reader: io.MultiReader(bytes.NewReader(head), f.source), source: f.source}
+
+	if sized, ok := f.source.(interface{ Len() int }); ok {
+		f.body = ReaderBody(&sizedPrefixReader{prefixReader: prefixed, head: n, rest: sized}, contentType)
+	} else {
+		f.body = ReaderBody(prefixed, contentType)
+	}
+
+	return f.body, nil
+}
+
+// prefixReader reads the sniffed bytes back before the rest of the source, which it closes.
+type prefixReader struct {
+	io.Reader
+	source io.Reader
+	read   int
+}
+
+func (r *prefixReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.read += n
+
+	return n, err
+}
+
+func (r *prefixReader) Close() error {
+	if closer, ok := r.source.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+type sizedPrefixReader struct {
+	*prefixReader
+	head int
+	rest interface{ Len() int }
+}
+
+func (r *sizedPrefixReader) Len() int { return max(r.head-r.read, 0) + r.rest.Len() }
+
+type bytesBody struct {
+	data        []byte
+	contentType string
+}
+
+// BytesBody sends b with the given content type and a known Content-Length, it is sent again as is on a retry.
+func BytesBody(b []byte, contentType string) IBodyParser {
+	return bytesBody{data: b, contentType: contentType}
+}
+
+func (f bytesBody) Parse() (io.Reader, error) { return bytes.NewReader(f.data), nil }
+
+func (f bytesBody) ContentType() string { return f.contentType }
+
+const headerValueTextPlain = "text/plain; charset=utf-8"
+
+// TextBody sends s as a text/plain UTF-8 body with a known Content-Length, see BytesBody.
+func TextBody(s string) IBodyParser {
+	return BytesBody([]byte(s), headerValueTextPlain)
+}
+
 type dumpBody struct{}
 
-func (f dumpBody) Parse() *bytes.Buffer { return new(bytes.Buffer) }
+func (f dumpBody) Parse() (io.Reader, error) { return new(bytes.Buffer), nil }
 
 func (f dumpBody) ContentType() string { return "" }
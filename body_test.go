@@ -1,30 +1,49 @@
 package room
 
 import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"strings"
 	"testing"
 )
 
+func parseString(t *testing.T, parser IBodyParser) string {
+	t.Helper()
+
+	reader, err := parser.Parse()
+	if err != nil {
+		t.Fatalf("Parse() returned unexpected error: %v", err)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("reading parsed body returned unexpected error: %v", err)
+	}
+
+	return string(data)
+}
+
 func TestJsonBody_Parse(t *testing.T) {
 	// Test case where JSON encoding is successful
 	data := map[string]interface{}{"key": "value"}
 	body := JsonBody{v: data}
-	buffer := body.Parse()
 	expected := `{"key":"value"}`
-	bufferString := strings.TrimRight(buffer.String(), "\n") // Remove trailing newline
+	bufferString := strings.TrimRight(parseString(t, &body), "\n") // Remove trailing newline
 	if bufferString != expected {
 		t.Errorf("JsonBody Parse() returned %s, expected %s", bufferString, expected)
 	}
 
 	// Test case where JSON encoding fails
-	defer func() {
-		if r := recover(); r == nil {
-			t.Error("JsonBody Parse() did not panic when JSON encoding failed")
-		}
-	}()
 	invalidData := make(chan int) // Invalid data for JSON encoding
 	invalidBody := JsonBody{v: invalidData}
-	_ = invalidBody.Parse()
+	if _, err := invalidBody.Parse(); err == nil {
+		t.Error("JsonBody Parse() did not return an error when JSON encoding failed")
+	}
 }
 
 func TestFormURLEncodedBodyAsStruct_Parse(t *testing.T) {
@@ -33,30 +52,42 @@ func TestFormURLEncodedBodyAsStruct_Parse(t *testing.T) {
 		Key2 int    `url:"key2"`
 	}{"value1", 42}
 	body := FormURLEncodedBody{v: mapData}
-	buffer := body.Parse()
+	result := parseString(t, &body)
 	expected := "key1=value1&key2=42"
-	if buffer.String() != expected {
-		t.Errorf("FormURLEncodedBody Parse() returned %s, expected %s", buffer.String(), expected)
+	if result != expected {
+		t.Errorf("FormURLEncodedBody Parse() returned %s, expected %s", result, expected)
 	}
 }
 
 func TestFormURLEncodedBodyAsMap_Parse(t *testing.T) {
 	mapData := map[string]any{"key1": "value1", "key2": "42"}
 	body := FormURLEncodedBody{v: mapData}
-	buffer := body.Parse()
+	result := parseString(t, &body)
 	expected := "key1=value1&key2=42"
-	if buffer.String() != expected {
-		t.Errorf("FormURLEncodedBody Parse() returned %s, expected %s", buffer.String(), expected)
+	if result != expected {
+		t.Errorf("FormURLEncodedBody Parse() returned %s, expected %s", result, expected)
+	}
+}
+
+func TestFormURLEncodedBody_ParseError(t *testing.T) {
+	body := FormURLEncodedBody{v: map[string]any{"key1": 42}}
+	if _, err := body.Parse(); err == nil {
+		t.Error("FormURLEncodedBody Parse() did not return an error for a non-string map value")
+	}
+
+	body = FormURLEncodedBody{v: 42}
+	if _, err := body.Parse(); err == nil {
+		t.Error("FormURLEncodedBody Parse() did not return an error when form encoding failed")
 	}
 }
 
 func TestDumpBody_Parse(t *testing.T) {
 	// Test DumpBody Parse() always returns an empty buffer
 	body := dumpBody{}
-	buffer := body.Parse()
+	result := parseString(t, body)
 	expected := ""
-	if buffer.String() != expected {
-		t.Errorf("DumpBody Parse() returned %s, expected empty", buffer.String())
+	if result != expected {
+		t.Errorf("DumpBody Parse() returned %s, expected empty", result)
 	}
 }
 
@@ -79,3 +110,258 @@ func TestNewFormURLEncodedBodyParser(t *testing.T) {
 		t.Error("NewFormURLEncodedBodyParser() did not return a FormURLEncodedBody instance")
 	}
 }
+
+func TestJSONBody(t *testing.T) {
+	bodyParser := JSONBody(map[string]any{"key": "value"})
+	if bodyParser.ContentType() != "application/json" {
+		t.Errorf("JSONBody() ContentType() returned %s, expected application/json", bodyParser.ContentType())
+	}
+	if result := strings.TrimRight(parseString(t, bodyParser), "\n"); result != `{"key":"value"}` {
+		t.Errorf("JSONBody() Parse() returned %s, expected {\"key\":\"value\"}", result)
+	}
+}
+
+func TestFormBody(t *testing.T) {
+	values := url.Values{}
+	values.Add("tag", "a&b")
+	values.Add("tag", "c d")
+	values.Add("name", "ç=1")
+
+	bodyParser := FormBody(values)
+	if bodyParser.ContentType() != headerValueFormEncoded {
+		t.Errorf("FormBody() ContentType() returned %s, expected %s", bodyParser.ContentType(), headerValueFormEncoded)
+	}
+
+	expected := "name=%C3%A7%3D1&tag=a%26b&tag=c+d"
+	if result := parseString(t, bodyParser); result != expected {
+		t.Errorf("FormBody() Parse() returned %s, expected %s", result, expected)
+	}
+
+	bodyParser = NewFormURLEncodedBodyParser(map[string][]string{"tag": {"a", "b"}})
+	if result := parseString(t, bodyParser); result != "tag=a&tag=b" {
+		t.Errorf("FormURLEncodedBody Parse() returned %s, expected tag=a&tag=b", result)
+	}
+}
+
+func TestReaderBody(t *testing.T) {
+	source := strings.NewReader("raw content")
+	bodyParser := ReaderBody(source, "application/octet-stream")
+
+	reader, _ := bodyParser.Parse()
+	if reader != source {
+		t.Error("ReaderBody() Parse() did not return the reader untouched")
+	}
+	if bodyParser.ContentType() != "application/octet-stream" {
+		t.Errorf("ReaderBody() ContentType() returned %s, expected application/octet-stream", bodyParser.ContentType())
+	}
+}
+
+func TestBytesBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(r.Header.Get("Content-Length") + " " + r.Header.Get("Content-Type") + " " + string(body)))
+	}))
+	defer server.Close()
+
+	bodyParser := BytesBody([]byte("raw content"), "application/octet-stream")
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		response, err := NewRequest(server.URL, WithMethod(POST), WithBody(bodyParser)).Send()
+		if expected := "11 application/octet-stream raw content"; err != nil || string(response.Data) != expected {
+			t.Errorf("BytesBody() sent (%s, %v) on send %d, expected %s", response.Data, err, attempt, expected)
+		}
+	}
+}
+
+func TestTextBody(t *testing.T) {
+	bodyParser := TextBody("héllo")
+
+	if result := parseString(t, bodyParser); result != "héllo" || parseString(t, bodyParser) != "héllo" {
+		t.Errorf("TextBody() Parse() returned %s, expected héllo on every call", result)
+	}
+	if bodyParser.ContentType() != "text/plain; charset=utf-8" {
+		t.Errorf("TextBody() ContentType() returned %s, expected text/plain; charset=utf-8", bodyParser.ContentType())
+	}
+	if reader, _ := bodyParser.Parse(); bodyLength(reader) != 6 {
+		t.Errorf("TextBody() Parse() returned a body of length %d, expected 6", bodyLength(reader))
+	}
+}
+
+func TestReaderBodyAutoDetect(t *testing.T) {
+	png := "\x89PNG\r\n\x1a\n" + strings.Repeat("x", 600)
+
+	tests := map[string]struct {
+		reader      io.Reader
+		contentType string
+		body        string
+	}{
+		"seeker":  {strings.NewReader(png), "image/png", png},
+		"buffer":  {bytes.NewBufferString("<html><body></body></html>"), "text/html; charset=utf-8", "<html><body></body></html>"},
+		"stream":  {io.MultiReader(strings.NewReader(png)), "image/png", png},
+		"empty":   {io.MultiReader(), "application/octet-stream", ""},
+		"unknown": {io.MultiReader(bytes.NewReader([]byte{0x00, 0x01, 0x02})), "application/octet-stream", "\x00\x01\x02"},
+	}
+
+	for name, test := range tests {
+		bodyParser := ReaderBodyAutoDetect(test.reader)
+
+		if contentType := bodyParser.ContentType(); contentType != test.contentType {
+			t.Errorf("ReaderBodyAutoDetect() of a %s has content type %s, expected %s", name, contentType, test.contentType)
+		}
+
+		if name == "buffer" {
+			if reader, _ := bodyParser.Parse(); bodyLength(reader) != int64(len(test.body)) {
+				t.Errorf("ReaderBodyAutoDetect() of a %s has length %d, expected %d", name, bodyLength(reader), len(test.body))
+			}
+		}
+
+		for attempt := 1; attempt <= 2; attempt++ {
+			if result := parseString(t, bodyParser); result != test.body {
+				t.Errorf("ReaderBodyAutoDetect() of a %s returned %q on parse %d, expected the sniffed bytes put back", name, result, attempt)
+			}
+		}
+	}
+}
+
+func TestRequest_SendWithPipeBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		if r.ContentLength != -1 || len(r.TransferEncoding) != 1 || r.TransferEncoding[0] != "chunked" {
+			w.WriteHeader(http.StatusLengthRequired)
+		}
+
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		for i := 0; i < 3; i++ {
+			_, _ = io.WriteString(pw, "chunk;")
+		}
+
+		_ = pw.Close()
+	}()
+
+	response, err := NewRequest(server.URL, WithMethod(POST), WithBody(ReaderBody(pr, "text/plain"))).Send()
+	if err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Send() returned status %d, expected the pipe sent with chunked encoding", response.StatusCode)
+	}
+	if body, _ := response.String(); body != "chunk;chunk;chunk;" {
+		t.Errorf("Send() echoed %s, expected chunk;chunk;chunk;", body)
+	}
+}
+
+// seekOnly hides the concrete type of the reader so net/http cannot snapshot it on its own.
+type seekOnly struct {
+	io.ReadSeeker
+}
+
+func TestRequest_SendReplaysReaderBody(t *testing.T) {
+	var attempts int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		if r.URL.Path == "/moved" {
+			http.Redirect(w, r, "/target", http.StatusTemporaryRedirect)
+
+			return
+		}
+
+		if attempts++; r.URL.Path == "/flaky" && attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		_, _ = io.WriteString(pw, "streamed once")
+		_ = pw.Close()
+	}()
+
+	tests := []struct {
+		path     string
+		reader   io.Reader
+		expected string
+	}{
+		{"/flaky", pr, "streamed once"},
+		{"/flaky", seekOnly{strings.NewReader("skip:seekable")}, "seekable"},
+		{"/moved", seekOnly{strings.NewReader("skip:redirected")}, "redirected"},
+	}
+
+	for _, test := range tests {
+		attempts = 0
+
+		if seeker, ok := test.reader.(io.Seeker); ok {
+			_, _ = seeker.Seek(5, io.SeekStart)
+		}
+
+		response, err := NewRequest(server.URL+test.path, WithMethod(POST), WithBody(ReaderBody(test.reader, "text/plain")),
+			WithRetryOnStatus(http.StatusServiceUnavailable), WithRetry(2, nil)).Send()
+
+		if body, _ := response.String(); err != nil || body != test.expected {
+			t.Errorf("Send() %s returned (%s, %v), expected the body replayed as %s", test.path, body, err, test.expected)
+		}
+	}
+}
+
+func TestRequest_SendLargePipeBody(t *testing.T) {
+	defer func(size int) { MaxBodyReplaySize = size }(MaxBodyReplaySize)
+	MaxBodyReplaySize = 1 << 10
+
+	var attempts int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		if attempts++; r.URL.Path == "/flaky" && attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(strconv.Itoa(len(body))))
+	}))
+	defer server.Close()
+
+	upload := func() io.Reader {
+		pr, pw := io.Pipe()
+
+		go func() {
+			_, _ = pw.Write(bytes.Repeat([]byte("x"), 1<<20))
+			_ = pw.Close()
+		}()
+
+		return pr
+	}
+
+	body := ReaderBody(upload(), "text/plain")
+	response, err := NewRequest(server.URL, WithMethod(POST), WithBody(body)).Send()
+
+	if sent, _ := response.String(); err != nil || sent != strconv.Itoa(1<<20) {
+		t.Errorf("Send() returned (%s, %v), expected the whole upload sent", sent, err)
+	}
+	if replay := body.(*readerBody).replay; replay != nil {
+		t.Errorf("ReaderBody kept %d bytes in memory, expected them released past MaxBodyReplaySize", replay.Len())
+	}
+
+	attempts = 0
+	_, err = NewRequest(server.URL+"/flaky", WithMethod(POST), WithBody(ReaderBody(upload(), "text/plain")),
+		WithRetryOnStatus(http.StatusServiceUnavailable), WithRetry(2, nil)).Send()
+
+	if !errors.Is(err, ErrBodyNotReplayable) {
+		t.Errorf("Send() returned %v on a retry, expected ErrBodyNotReplayable", err)
+	}
+}
@@ -0,0 +1,185 @@
+package room
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures opening the breaker, 5 when zero.
+	FailureThreshold int
+	// Cooldown is how long the breaker stays open before letting a probe through, 30s when zero.
+	Cooldown time.Duration
+	// IsFailure decides whether an attempt counts as a failure, DefaultBreakerFailure when nil.
+	IsFailure func(response *http.Response, err error) bool
+}
+
+// DefaultBreakerFailure counts network errors and 5xx responses, cancelled requests do not count.
+func DefaultBreakerFailure(response *http.Response, err error) bool {
+	if err != nil {
+		return !IsCanceled(err)
+	}
+
+	return response.StatusCode >= 500
+}
+
+// CircuitBreaker stops sending after FailureThreshold consecutive failures. Once Cooldown elapsed a single probe
+// is let through while the breaker is half-open: a success closes the breaker, a failure opens it again.
+type CircuitBreaker struct {
+	mu       sync.Mutex
+	cfg      BreakerConfig
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+	// generation changes whenever the breaker opens or closes, the attempts sent before tell nothing about the new state.
+	generation int
+	now        func() time.Time
+}
+
+// breakerAttempt is an attempt let through by allow, to report to record.
+type breakerAttempt struct {
+	probe      bool
+	generation int
+}
+
+func NewCircuitBreaker(cfg BreakerConfig) *CircuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = 30 * time.Second
+	}
+
+	if cfg.IsFailure == nil {
+		cfg.IsFailure = DefaultBreakerFailure
+	}
+
+	return &CircuitBreaker{cfg: cfg, now: time.Now}
+}
+
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.cfg.Cooldown {
+		return BreakerHalfOpen
+	}
+
+	return b.state
+}
+
+// allow reports whether an attempt may be sent, and whether it is the probe of a half-open breaker.
+func (b *CircuitBreaker) allow() (breakerAttempt, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.cfg.Cooldown {
+		b.state = BreakerHalfOpen
+	}
+
+	switch {
+	case b.state == BreakerClosed:
+		return breakerAttempt{generation: b.generation}, nil
+	case b.state == BreakerHalfOpen && !b.probing:
+		b.probing = true
+
+		return breakerAttempt{probe: true, generation: b.generation}, nil
+	default:
+		return breakerAttempt{}, ErrCircuitOpen
+	}
+}
+
+func (b *CircuitBreaker) record(attempt breakerAttempt, response *http.Response, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if attempt.probe {
+		b.probing = false
+	}
+
+	// A cancelled attempt tells nothing about the host, a cancelled probe lets the next attempt probe instead.
+	if IsCanceled(err) {
+		return
+	}
+
+	// A late attempt sent while closed neither closes a breaker opened since nor extends its cooldown.
+	if attempt.generation != b.generation {
+		return
+	}
+
+	if !b.cfg.IsFailure(response, err) {
+		if b.state != BreakerClosed {
+			b.generation++
+		}
+
+		b.state, b.failures = BreakerClosed, 0
+
+		return
+	}
+
+	b.failures++
+
+	if attempt.probe || b.failures >= b.cfg.FailureThreshold {
+		b.state, b.openedAt = BreakerOpen, b.now()
+		b.generation++
+	}
+}
+
+// WithCircuitBreaker guards the request with a breaker per host, see CircuitBreakerMiddleware.
+// Requests built with the same option value share the breakers, as the requests of a Client using WithCircuitBreakerClient do.
+func WithCircuitBreaker(cfg BreakerConfig) OptionRequest {
+	return WithMiddleware(CircuitBreakerMiddleware(cfg))
+}
+
+// CircuitBreakerMiddleware keeps a CircuitBreaker per host and fails attempts with ErrCircuitOpen while the breaker
+// of the host of the request is open. DefaultRetryCondition does not retry ErrCircuitOpen.
+func CircuitBreakerMiddleware(cfg BreakerConfig) Middleware {
+	breakers := newHostMap(func() *CircuitBreaker {
+		return NewCircuitBreaker(cfg)
+	})
+
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			breaker := breakers.get(req.URL.Host)
+
+			attempt, err := breaker.allow()
+
+			if err != nil {
+				closeReaders(req.Body)
+
+				return nil, err
+			}
+
+			response, err := next(req)
+
+			breaker.record(attempt, response, err)
+
+			return response, err
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package room
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker_States(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(BreakerConfig{FailureThreshold: 2, Cooldown: time.Minute})
+	breaker.now = func() time.Time { return now }
+
+	failed := &http.Response{StatusCode: http.StatusServiceUnavailable}
+	succeeded := &http.Response{StatusCode: http.StatusOK}
+
+	for i := 0; i < 2; i++ {
+		attempt, err := breaker.allow()
+		if err != nil {
+			t.Fatalf("allow() returned %v while closed, expected nil", err)
+		}
+		breaker.record(attempt, failed, nil)
+	}
+
+	if _, err := breaker.allow(); !errors.Is(err, ErrCircuitOpen) || breaker.State() != BreakerOpen {
+		t.Errorf("allow() returned %v in state %s, expected ErrCircuitOpen once open", err, breaker.State())
+	}
+
+	now = now.Add(time.Minute)
+	if breaker.State() != BreakerHalfOpen {
+		t.Errorf("State() returned %s after the cooldown, expected half-open", breaker.State())
+	}
+
+	// An attempt sent while closed succeeding late leaves the breaker open.
+	late := breakerAttempt{}
+	breaker.record(late, succeeded, nil)
+
+	probe, err := breaker.allow()
+	if err != nil || !probe.probe {
+		t.Fatalf("allow() returned (%v, %v) after the cooldown, expected the probe", probe.probe, err)
+	}
+	if _, err = breaker.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("allow() returned %v during the probe, expected ErrCircuitOpen", err)
+	}
+
+	breaker.record(probe, failed, nil)
+	if breaker.State() != BreakerOpen {
+		t.Errorf("State() returned %s after a failed probe, expected open", breaker.State())
+	}
+
+	breaker.record(late, succeeded, nil)
+	if breaker.State() != BreakerOpen {
+		t.Errorf("State() returned %s after a late success, expected open", breaker.State())
+	}
+
+	now = now.Add(time.Minute)
+	probe, _ = breaker.allow()
+	breaker.record(probe, succeeded, nil)
+	if breaker.State() != BreakerClosed {
+		t.Errorf("State() returned %s after a successful probe, expected closed", breaker.State())
+	}
+}
+
+func TestClient_SendWithCircuitBreaker(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithCircuitBreakerClient(BreakerConfig{FailureThreshold: 2, Cooldown: time.Hour}))
+
+	for i := 0; i < 2; i++ {
+		if response, err := client.Get("/users").Send(); err != nil || response.StatusCode != http.StatusBadGateway {
+			t.Fatalf("Send() returned (%d, %v), expected the 502 while closed", response.StatusCode, err)
+		}
+	}
+
+	if _, err := client.Get("/orders").Send(); !errors.Is(err, ErrCircuitOpen) || calls != 2 {
+		t.Errorf("Send() returned %v after %d calls, expected ErrCircuitOpen without reaching the host", err, calls)
+	}
+
+	if DefaultRetryCondition(nil, ErrCircuitOpen) {
+		t.Error("DefaultRetryCondition() retried ErrCircuitOpen")
+	}
+}
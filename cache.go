@@ -0,0 +1,149 @@
+package room
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	headerKeyETag            = "ETag"
+	headerKeyLastModified    = "Last-Modified"
+	headerKeyIfNoneMatch     = "If-None-Match"
+	headerKeyIfModifiedSince = "If-Modified-Since"
+	headerKeyCacheControl    = "Cache-Control"
+)
+
+// CachedResponse is a response kept by a CacheStore, Body holds it as received, still encoded if it was.
+type CachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// CacheStore keeps responses by URL for WithCache, implementations must be safe for concurrent use.
+type CacheStore interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, response *CachedResponse)
+}
+
+// MemoryCache is an in-memory CacheStore, entries are never evicted.
+type MemoryCache struct {
+	mu      sync.RWMutex
+	entries map[string]*CachedResponse
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: map[string]*CachedResponse{}}
+}
+
+func (c *MemoryCache) Get(key string) (*CachedResponse, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	response, ok := c.entries[key]
+
+	return response, ok
+}
+
+func (c *MemoryCache) Set(key string, response *CachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = response
+}
+
+// WithCache revalidates GET requests against store, see CacheMiddleware.
+func WithCache(store CacheStore) OptionRequest {
+	return WithMiddleware(CacheMiddleware(store))
+}
+
+// CacheMiddleware stores 200 responses to GET requests carrying an ETag or a Last-Modified header by URL,
+// unless they are marked no-store. Later GET requests to the same URL send If-None-Match and If-Modified-Since,
+// and a 304 answer is replaced by the stored response with the headers of the 304 applied on top.
+func CacheMiddleware(store CacheStore) Middleware {
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Method != http.MethodGet {
+				return next(req)
+			}
+
+			key := req.URL.String()
+			cached, found := store.Get(key)
+
+			if found {
+				setConditionalHeaders(req, cached.Header)
+			}
+
+			response, err := next(req)
+
+			if err != nil {
+				return response, err
+			}
+
+			if response.StatusCode == http.StatusNotModified && found {
+				discardBody(response)
+
+				return cachedHTTPResponse(req, cached, response.Header), nil
+			}
+
+			if !cacheable(response) {
+				return response, nil
+			}
+
+			body, err := io.ReadAll(response.Body)
+			_ = response.Body.Close()
+
+			if err != nil {
+				return nil, fmt.Errorf("read response body: %w", err)
+			}
+
+			store.Set(key, &CachedResponse{StatusCode: response.StatusCode, Header: response.Header.Clone(), Body: body})
+
+			response.Body = io.NopCloser(bytes.NewReader(body))
+
+			return response, nil
+		}
+	}
+}
+
+// setConditionalHeaders leaves validators the caller set on the request untouched.
+func setConditionalHeaders(req *http.Request, cached http.Header) {
+	if etag := cached.Get(headerKeyETag); etag != "" && req.Header.Get(headerKeyIfNoneMatch) == "" {
+		req.Header.Set(headerKeyIfNoneMatch, etag)
+	}
+
+	if modified := cached.Get(headerKeyLastModified); modified != "" && req.Header.Get(headerKeyIfModifiedSince) == "" {
+		req.Header.Set(headerKeyIfModifiedSince, modified)
+	}
+}
+
+func cacheable(response *http.Response) bool {
+	if response.StatusCode != http.StatusOK {
+		return false
+	}
+
+	if response.Header.Get(headerKeyETag) == "" && response.Header.Get(headerKeyLastModified) == "" {
+		return false
+	}
+
+	return !strings.Contains(strings.ToLower(response.Header.Get(headerKeyCacheControl)), "no-store")
+}
+
+func cachedHTTPResponse(req *http.Request, cached *CachedResponse, updated http.Header) *http.Response {
+	header := cached.Header.Clone()
+
+	for key, values := range updated {
+		if key != "Content-Length" {
+			header[key] = values
+		}
+	}
+
+	response := bufferedResponse(req, cached.StatusCode, cached.Body)
+	response.Header = header
+
+	return response
+}
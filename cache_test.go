@@ -0,0 +1,72 @@
+package room
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequest_SendWithCache(t *testing.T) {
+	var conditional []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conditional = append(conditional, r.Header.Get("If-None-Match"))
+
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("X-Served", r.URL.Path)
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.Header().Set("X-Served", "revalidated")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		_, _ = w.Write([]byte("users"))
+	}))
+	defer server.Close()
+
+	cache := NewMemoryCache()
+
+	first, err := NewRequest(server.URL+"/users", WithCache(cache)).Send()
+	if err != nil || string(first.Data) != "users" {
+		t.Fatalf("WithCache() Send() returned (%s, %v), expected users", first.Data, err)
+	}
+
+	second, err := NewRequest(server.URL+"/users", WithCache(cache)).Send()
+	if err != nil || second.StatusCode != http.StatusOK || string(second.Data) != "users" {
+		t.Errorf("WithCache() Send() returned (%d, %s, %v), expected the cached users with status 200", second.StatusCode, second.Data, err)
+	}
+	if second.Header.Get("X-Served") != "revalidated" {
+		t.Errorf("WithCache() returned X-Served %s, expected the headers of the 304 applied", second.Header.Get("X-Served"))
+	}
+	if len(conditional) != 2 || conditional[0] != "" || conditional[1] != `"v1"` {
+		t.Errorf("WithCache() sent If-None-Match %q, expected none then \"v1\"", conditional)
+	}
+
+	if _, err = NewRequest(server.URL+"/users", WithMethod(POST), WithCache(cache)).Send(); err != nil || conditional[2] != "" {
+		t.Errorf("WithCache() sent If-None-Match %q with a POST, expected none", conditional[2])
+	}
+}
+
+func TestCacheMiddleware_Cacheable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+
+		switch r.URL.Path {
+		case "/private":
+			w.Header().Set("Cache-Control", "private, no-store")
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	cache := NewMemoryCache()
+
+	for _, path := range []string{"/private", "/missing"} {
+		_, _ = NewRequest(server.URL+path, WithCache(cache)).Send()
+
+		if _, found := cache.Get(server.URL + path); found {
+			t.Errorf("CacheMiddleware() stored the response of %s", path)
+		}
+	}
+}
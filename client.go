@@ -0,0 +1,127 @@
+package room
+
+import (
+	"net/http"
+	"time"
+)
+
+// Client builds requests sharing the same base URL and defaults, so they are not repeated on every call.
+// NewRequest stays the way to go for one-off requests.
+type Client struct {
+	baseUrl     string
+	Header      IHeader
+	timeout     time.Duration
+	httpClient  *http.Client
+	middleware  []Middleware
+	hostHeaders hostHeaders
+}
+
+type OptionClient func(client *Client)
+
+// WithHeaderClient sets headers sent with every request built by the client.
+// Headers of the request itself override them on key collision.
+func WithHeaderClient(header IHeader) OptionClient {
+	return func(client *Client) {
+		client.Header = header
+	}
+}
+
+// WithTimeoutClient sets the default timeout of the requests, WithTimeout overrides it per request.
+func WithTimeoutClient(timeout time.Duration) OptionClient {
+	return func(client *Client) {
+		client.timeout = timeout
+	}
+}
+
+// WithHTTPClient sends every request built by the client with httpClient, WithClient overrides it per request.
+func WithHTTPClient(httpClient *http.Client) OptionClient {
+	return func(client *Client) {
+		client.httpClient = httpClient
+	}
+}
+
+// WithMiddlewareClient runs middlewares around every request built by the client, before those of the request itself.
+func WithMiddlewareClient(middlewares ...Middleware) OptionClient {
+	return func(client *Client) {
+		client.middleware = append(client.middleware, middlewares...)
+	}
+}
+
+// WithRateLimitClient shares a token bucket per host between all the requests built by the client, see RateLimitMiddleware.
+func WithRateLimitClient(limit Limit, burst int) OptionClient {
+	return WithMiddlewareClient(RateLimitMiddleware(limit, burst))
+}
+
+// WithCircuitBreakerClient shares a circuit breaker per host between all the requests built by the client,
+// see CircuitBreakerMiddleware.
+func WithCircuitBreakerClient(cfg BreakerConfig) OptionClient {
+	return WithMiddlewareClient(CircuitBreakerMiddleware(cfg))
+}
+
+// NewClient creates a client whose requests are joined to baseUrl with SetBaseUrl, an empty baseUrl leaves paths as they are.
+func NewClient(baseUrl string, opts ...OptionClient) *Client {
+	c := &Client{baseUrl: baseUrl}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// NewRequest creates a request carrying the client defaults, see NewRequest.
+func (c *Client) NewRequest(path string, opts ...OptionRequest) *Request {
+	r := NewRequest(path, append(c.defaults(), opts...)...)
+
+	r.Header = overrideHeader(c.Header, r.Header)
+
+	if c.baseUrl != "" {
+		r.SetBaseUrl(c.baseUrl)
+	}
+
+	if len(c.hostHeaders) > 0 {
+		r.hostHeaders = c.hostHeaders
+		r.checkRedirect = c.hostHeaders.checkRedirect(r.checkRedirect)
+	}
+
+	return r
+}
+
+func (c *Client) Get(path string, opts ...OptionRequest) *Request {
+	return c.NewRequest(path, append([]OptionRequest{WithMethod(GET)}, opts...)...)
+}
+
+func (c *Client) Post(path string, body IBodyParser, opts ...OptionRequest) *Request {
+	return c.NewRequest(path, append([]OptionRequest{WithMethod(POST), WithBody(body)}, opts...)...)
+}
+
+func (c *Client) Put(path string, body IBodyParser, opts ...OptionRequest) *Request {
+	return c.NewRequest(path, append([]OptionRequest{WithMethod(PUT), WithBody(body)}, opts...)...)
+}
+
+func (c *Client) Patch(path string, body IBodyParser, opts ...OptionRequest) *Request {
+	return c.NewRequest(path, append([]OptionRequest{WithMethod(PATCH), WithBody(body)}, opts...)...)
+}
+
+func (c *Client) Delete(path string, opts ...OptionRequest) *Request {
+	return c.NewRequest(path, append([]OptionRequest{WithMethod(DELETE)}, opts...)...)
+}
+
+// defaults are applied before the options of the request so the latter win.
+func (c *Client) defaults() []OptionRequest {
+	var opts []OptionRequest
+
+	if c.timeout > 0 {
+		opts = append(opts, WithTimeout(c.timeout))
+	}
+
+	if c.httpClient != nil {
+		opts = append(opts, WithClient(c.httpClient))
+	}
+
+	if len(c.middleware) > 0 {
+		opts = append(opts, WithMiddleware(c.middleware...))
+	}
+
+	return opts
+}
@@ -0,0 +1,71 @@
+package room
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_NewRequestWithDefaultHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "default-key" {
+			t.Errorf("server received X-Api-Key %q, expected default-key", r.Header.Get("X-Api-Key"))
+		}
+		if values := r.Header.Values("Accept"); len(values) != 1 || values[0] != "text/xml" {
+			t.Errorf("server received Accept %v, expected [text/xml]", values)
+		}
+	}))
+	defer server.Close()
+
+	defaults := NewHeader().Add("X-Api-Key", "default-key").Add("Accept", "application/json")
+	client := NewClient("", WithHeaderClient(defaults))
+
+	_, err := client.NewRequest(server.URL, WithHeader(NewHeader().Add("accept", "text/xml"))).Send()
+	if err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+
+	if defaults.Get("Accept") != "application/json" {
+		t.Error("Client NewRequest() modified the default headers")
+	}
+}
+
+func TestClient_Methods(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(r.Method + " " + r.URL.Path + " " + string(body)))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/v1", WithTimeoutClient(time.Second))
+	body := ReaderBody(strings.NewReader("payload"), "text/plain")
+
+	tests := []struct {
+		request  *Request
+		expected string
+	}{
+		{client.Get("users"), "GET /v1/users "},
+		{client.Post("/users", body), "POST /v1/users payload"},
+		{client.Delete("users/1"), "DELETE /v1/users/1 "},
+	}
+
+	for _, test := range tests {
+		response, err := test.request.Send()
+		if err != nil {
+			t.Fatalf("Send() returned unexpected error: %v", err)
+		}
+		if string(response.Data) != test.expected {
+			t.Errorf("server received %q, expected %q", response.Data, test.expected)
+		}
+	}
+
+	if r := client.Get("users"); r.contextBuilder != NewContextBuilder(time.Second) {
+		t.Error("Client did not apply its default timeout")
+	}
+	if r := client.Get("users", WithTimeout(time.Minute)); r.contextBuilder != NewContextBuilder(time.Minute) {
+		t.Error("WithTimeout() did not override the client timeout")
+	}
+}
@@ -0,0 +1,56 @@
+package room
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/WEG-Technology/room/store"
+)
+
+// Clone returns a copy of the request whose headers, query, cookies, path params and options can be changed without
+// affecting r, to derive variations of a base request or send them from several goroutines.
+// The client, context, cookie jar and transport stay shared, so clones reuse the connections of r.
+// Body parsers are shared too, they are parsed again on every attempt. A ReaderBody, ReaderBodyAutoDetect or multipart
+// file reader is then rewound or replayed for every clone, as on a retry: clones sharing one must not be sent concurrently.
+func (r *Request) Clone() *Request {
+	c := *r
+
+	if r.Header != nil {
+		c.Header = NewHeader().Merge(r.Header)
+	}
+
+	if query, ok := r.Query.(IMapQuery); ok {
+		c.Query = IMapQuery{store.NewMapStore(query.v.All())}
+	}
+
+	if r.Cookies != nil {
+		c.Cookies = make([]*http.Cookie, len(r.Cookies))
+
+		for i, cookie := range r.Cookies {
+			if cookie != nil {
+				copied := *cookie
+				c.Cookies[i] = &copied
+			}
+		}
+	}
+
+	if r.retry != nil {
+		retry := *r.retry
+		c.retry = &retry
+	}
+
+	// MultipartFormDataBody stores its boundary on Parse, concurrent sends need a body each.
+	if body, ok := r.BodyParser.(*MultipartFormDataBody); ok {
+		copied := *body
+		c.BodyParser = &copied
+	}
+
+	c.pathParams = maps.Clone(r.pathParams)
+	c.middlewares = slices.Clip(r.middlewares)
+	c.transportOpts = slices.Clip(r.transportOpts)
+	c.signers = slices.Clip(r.signers)
+	c.values = slices.Clip(r.values)
+
+	return &c
+}
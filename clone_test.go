@@ -0,0 +1,44 @@
+package room
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequest_Clone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, _ := r.Cookie("session")
+		_, _ = w.Write([]byte(r.URL.RequestURI() + " " + r.Header.Get("X-Tenant") + " " + cookie.Value))
+	}))
+	defer server.Close()
+
+	original := NewRequest(server.URL+"/users/{id}",
+		WithHeader(NewHeader().Add("X-Tenant", "acme")),
+		WithQuery(NewMapQuery().Add("page", "1")),
+		WithCookies(&http.Cookie{Name: "session", Value: "a"}),
+		WithPathParams(map[string]string{"id": "1"}),
+		WithRetry(2, nil),
+	)
+
+	clone := original.Clone()
+	clone.Header.(*Header).Set("X-Tenant", "globex")
+	clone.Query.(IMapQuery).Add("page", "2")
+	clone.Cookies[0].Value = "b"
+	clone.SetPathParam("id", "2")
+	WithRetryOnStatus(http.StatusConflict)(clone)
+
+	response, err := original.Send()
+	if body := string(response.Data); err != nil || body != "/users/1?page=1 acme a" {
+		t.Errorf("Send() of the original returned (%s, %v), expected /users/1?page=1 acme a", body, err)
+	}
+
+	response, err = clone.Send()
+	if body := string(response.Data); err != nil || body != "/users/2?page=1&page=2 globex b" {
+		t.Errorf("Send() of the clone returned (%s, %v), expected /users/2?page=1&page=2 globex b", body, err)
+	}
+
+	if original.retry == clone.retry {
+		t.Error("Clone() shared the retry policy of the original")
+	}
+}
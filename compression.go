@@ -0,0 +1,216 @@
+package room
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	headerKeyContentEncoding = "Content-Encoding"
+	encodingGzip             = "gzip"
+)
+
+// WithGzipBody gzips the body produced by the BodyParser and sends it with "Content-Encoding: gzip".
+// Requests without a body are left untouched.
+func WithGzipBody() OptionRequest {
+	return func(request *Request) {
+		request.gzipBody = true
+	}
+}
+
+// gzipReader compresses body on the fly while the transport reads from it.
+func gzipReader(body io.Reader) io.Reader {
+	pr, pw := io.Pipe()
+
+	go func() {
+		writer := gzip.NewWriter(pw)
+
+		_, err := io.Copy(writer, body)
+
+		if closeErr := writer.Close(); err == nil {
+			err = closeErr
+		}
+
+		_ = pw.CloseWithError(err)
+	}()
+
+	return pr
+}
+
+func isEmptyBody(body io.Reader) bool {
+	if body == nil || body == http.NoBody {
+		return true
+	}
+
+	if sized, ok := body.(interface{ Len() int }); ok {
+		return sized.Len() == 0
+	}
+
+	return false
+}
+
+const encodingDeflate = "deflate"
+
+// Decoder returns a reader decoding r, a response body sent with the Content-Encoding it is registered for.
+// A returned io.Closer is closed along with the body.
+type Decoder func(r io.Reader) (io.Reader, error)
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = map[string]Decoder{}
+)
+
+// RegisterDecoder decodes response bodies sent with Content-Encoding encoding on top of gzip and deflate,
+// keeping room free of a compression dependency. Brotli and zstd can be added with the library of your choice,
+// github.com/andybalholm/brotli and github.com/klauspost/compress/zstd for instance:
+//
+//	room.RegisterDecoder("br", func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil })
+//	room.RegisterDecoder("zstd", func(r io.Reader) (io.Reader, error) {
+//		decoder, err := zstd.NewReader(r)
+//		return decoder.IOReadCloser(), err
+//	})
+//
+// Once a decoder is registered, requests not setting their own Accept-Encoding advertise it along with gzip and deflate.
+// A nil decoder removes the encoding. WithoutDecompression still returns the raw encoded bytes.
+func RegisterDecoder(encoding string, decoder Decoder) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+
+	encoding = strings.ToLower(encoding)
+
+	if decoder == nil {
+		delete(decoders, encoding)
+	} else {
+		decoders[encoding] = decoder
+	}
+}
+
+func registeredDecoder(encoding string) (Decoder, bool) {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+
+	decoder, ok := decoders[encoding]
+
+	return decoder, ok
+}
+
+// acceptEncoding lists the encodings room decodes, empty when only gzip and deflate are, leaving the transport
+// to ask for gzip as it does by default.
+func acceptEncoding() string {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+
+	if len(decoders) == 0 {
+		return ""
+	}
+
+	encodings := []string{encodingGzip, encodingDeflate}
+
+	for encoding := range decoders {
+		if encoding != encodingGzip && encoding != encodingDeflate {
+			encodings = append(encodings, encoding)
+		}
+	}
+
+	sort.Strings(encodings[2:])
+
+	return strings.Join(encodings, ", ")
+}
+
+// WithoutDecompression keeps gzip and deflate encoded response bodies as they were received.
+func WithoutDecompression() OptionRequest {
+	return func(request *Request) {
+		request.rawResponse = true
+	}
+}
+
+// decompressBody swaps a gzip, deflate or registered encoding body for a decoding reader.
+// Content-Encoding and Content-Length are dropped since they no longer describe the body.
+func decompressBody(response *http.Response) {
+	if response.Body == nil {
+		return
+	}
+
+	var decoder func(r *bufio.Reader) (io.Reader, error)
+
+	encoding := strings.ToLower(strings.TrimSpace(response.Header.Get(headerKeyContentEncoding)))
+
+	if registered, ok := registeredDecoder(encoding); ok {
+		decoder = func(r *bufio.Reader) (io.Reader, error) { return registered(r) }
+	} else {
+		switch encoding {
+		case encodingGzip, "x-gzip":
+			decoder = func(r *bufio.Reader) (io.Reader, error) { return gzip.NewReader(r) }
+		case encodingDeflate:
+			decoder = newDeflateReader
+		default:
+			return
+		}
+	}
+
+	response.Body = &decodingBody{raw: response.Body, newDecoder: decoder}
+	response.Header.Del(headerKeyContentEncoding)
+	response.Header.Del("Content-Length")
+	response.ContentLength = -1
+	response.Uncompressed = true
+}
+
+// newDeflateReader accepts both zlib wrapped streams, which the spec asks for, and the raw deflate some servers send.
+func newDeflateReader(r *bufio.Reader) (io.Reader, error) {
+	header, err := r.Peek(2)
+
+	if err == nil && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(r)
+	}
+
+	return flate.NewReader(r), nil
+}
+
+// decodingBody creates its decoder on the first read so an empty body reads as empty instead of failing.
+type decodingBody struct {
+	raw        io.ReadCloser
+	newDecoder func(r *bufio.Reader) (io.Reader, error)
+	decoder    io.Reader
+}
+
+func (b *decodingBody) Read(p []byte) (int, error) {
+	if b.decoder == nil {
+		buffered := bufio.NewReader(b.raw)
+
+		if _, err := buffered.Peek(1); err != nil {
+			return 0, err
+		}
+
+		decoder, err := b.newDecoder(buffered)
+
+		if err != nil {
+			return 0, fmt.Errorf("decompress response body: %w", err)
+		}
+
+		b.decoder = decoder
+	}
+
+	n, err := b.decoder.Read(p)
+
+	if err != nil && err != io.EOF {
+		err = fmt.Errorf("decompress response body: %w", err)
+	}
+
+	return n, err
+}
+
+func (b *decodingBody) Close() error {
+	if closer, ok := b.decoder.(io.Closer); ok {
+		_ = closer.Close()
+	}
+
+	return b.raw.Close()
+}
@@ -0,0 +1,170 @@
+package room
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequest_SendWithGzipBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "gzip" {
+			t.Errorf("server received Content-Encoding %q, expected gzip", r.Header.Get("Content-Encoding"))
+		}
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("server received Content-Type %q, expected application/json", r.Header.Get("Content-Type"))
+		}
+
+		reader, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Fatalf("server could not open gzip body: %v", err)
+		}
+		body, _ := io.ReadAll(reader)
+		if strings.TrimSpace(string(body)) != `{"key":"value"}` {
+			t.Errorf("server received body %q", body)
+		}
+	}))
+	defer server.Close()
+
+	_, err := NewRequest(server.URL, WithMethod(POST), WithGzipBody(), WithBody(JSONBody(map[string]any{"key": "value"}))).Send()
+	if err != nil {
+		t.Errorf("Send() returned unexpected error: %v", err)
+	}
+}
+
+func TestRequest_SendWithGzipBodyWithoutBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "" {
+			t.Errorf("server received Content-Encoding %q for a request without body", r.Header.Get("Content-Encoding"))
+		}
+	}))
+	defer server.Close()
+
+	_, _ = NewRequest(server.URL, WithGzipBody()).Send()
+}
+
+func gzipped(t *testing.T, s string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, _ = writer.Write([]byte(s))
+	_ = writer.Close()
+
+	return buf.Bytes()
+}
+
+func TestRequest_SendDecompressesResponse(t *testing.T) {
+	var deflated bytes.Buffer
+	zw := zlib.NewWriter(&deflated)
+	_, _ = zw.Write([]byte("deflated"))
+	_ = zw.Close()
+
+	tests := []struct {
+		encoding string
+		body     []byte
+		expected string
+	}{
+		{"gzip", gzipped(t, "gzipped"), "gzipped"},
+		{"deflate", deflated.Bytes(), "deflated"},
+		{"gzip", nil, ""},
+	}
+
+	for _, test := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Encoding", test.encoding)
+			_, _ = w.Write(test.body)
+		}))
+
+		response, err := NewRequest(server.URL, WithHeader(NewHeader().Add("Accept-Encoding", test.encoding))).Send()
+		if err != nil {
+			t.Errorf("Send() returned unexpected error for %s: %v", test.encoding, err)
+		}
+		if string(response.Data) != test.expected {
+			t.Errorf("Send() returned body %q for %s, expected %q", response.Data, test.encoding, test.expected)
+		}
+		if response.Header.Get("Content-Encoding") != "" {
+			t.Errorf("Send() kept Content-Encoding %q on a decompressed body", response.Header.Get("Content-Encoding"))
+		}
+
+		server.Close()
+	}
+}
+
+func TestRequest_SendWithoutDecompression(t *testing.T) {
+	body := gzipped(t, "gzipped")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	response, err := NewRequest(server.URL, WithHeader(NewHeader().Add("Accept-Encoding", "gzip")), WithoutDecompression()).Send()
+	if err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+	if !bytes.Equal(response.Data, body) {
+		t.Error("Send() decompressed the body despite WithoutDecompression()")
+	}
+	if response.Header.Get("Content-Encoding") != "gzip" {
+		t.Error("Send() dropped Content-Encoding despite WithoutDecompression()")
+	}
+}
+
+func TestRequest_SendWithCorruptGzipResponse(t *testing.T) {
+	body := gzipped(t, "gzipped")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(body[:len(body)/2])
+	}))
+	defer server.Close()
+
+	response, err := NewRequest(server.URL, WithHeader(NewHeader().Add("Accept-Encoding", "gzip"))).Send()
+	if err == nil || !strings.Contains(err.Error(), "decompress") {
+		t.Errorf("Send() returned %v for a truncated gzip body, expected a decompression error", err)
+	}
+	if err := response.JSON(&struct{}{}); err == nil {
+		t.Error("Response JSON() did not return the body read error")
+	}
+}
+
+func TestRequest_SendWithRegisteredDecoder(t *testing.T) {
+	RegisterDecoder("B64", func(r io.Reader) (io.Reader, error) { return base64.NewDecoder(base64.StdEncoding, r), nil })
+	defer RegisterDecoder("b64", nil)
+
+	if encodings := acceptEncoding(); encodings != "gzip, deflate, b64" {
+		t.Errorf("acceptEncoding() returned %s, expected gzip, deflate, b64", encodings)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.Header.Get("Accept-Encoding"), "b64") {
+			w.Header().Set("Content-Encoding", "b64")
+			_, _ = w.Write([]byte(base64.StdEncoding.EncodeToString([]byte("decoded"))))
+			return
+		}
+
+		_, _ = w.Write([]byte("plain " + r.Header.Get("Accept-Encoding")))
+	}))
+	defer server.Close()
+
+	response, err := NewRequest(server.URL).Send()
+	if err != nil || string(response.Data) != "decoded" || response.Header.Get("Content-Encoding") != "" {
+		t.Errorf("Send() returned (%s, %v), expected the body decoded by the registered decoder", response.Data, err)
+	}
+
+	response, _ = NewRequest(server.URL, WithHeader(NewHeader().Add("Accept-Encoding", "identity"))).Send()
+	if string(response.Data) != "plain identity" {
+		t.Errorf("Send() returned %s, expected the Accept-Encoding of the request kept", response.Data)
+	}
+
+	response, _ = NewRequest(server.URL, WithoutDecompression(), WithHeader(NewHeader().Add("Accept-Encoding", "b64"))).Send()
+	if string(response.Data) != base64.StdEncoding.EncodeToString([]byte("decoded")) || response.Header.Get("Content-Encoding") != "b64" {
+		t.Errorf("Send() WithoutDecompression returned %s, expected the raw body", response.Data)
+	}
+}
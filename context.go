@@ -5,6 +5,10 @@ import (
 	"time"
 )
 
+// DefaultTimeout bounds a Send given neither a context nor a context builder, zero leaving it unbounded.
+// Set it once at startup, WithTimeout, WithContextBuilder, WithContext and WithTimeoutClient override it.
+var DefaultTimeout = 30 * time.Second
+
 type Context struct {
 	Ctx    context.Context
 	Cancel context.CancelFunc
@@ -19,14 +23,18 @@ type ContextBuilder struct {
 }
 
 func (b ContextBuilder) Build() Context {
+	return b.build(context.Background())
+}
+
+func (b ContextBuilder) build(parent context.Context) Context {
 	if b.timeout == 0 {
 		return Context{
-			Ctx:    context.Background(),
+			Ctx:    parent,
 			Cancel: nil,
 		}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), b.timeout)
+	ctx, cancel := context.WithTimeout(parent, b.timeout)
 
 	return Context{
 		Ctx:    ctx,
@@ -32,3 +32,27 @@ func TestContextBuilder_Build(t *testing.T) {
 		t.Error("Cancel function is nil when timeout is set")
 	}
 }
+
+func TestDefaultTimeout(t *testing.T) {
+	defer func(timeout time.Duration) { DefaultTimeout = timeout }(DefaultTimeout)
+
+	DefaultTimeout = time.Minute
+
+	ctx := NewRequest("http://localhost").context()
+	if deadline, ok := ctx.Ctx.Deadline(); !ok || time.Until(deadline) < 50*time.Second {
+		t.Errorf("context() has deadline %v, expected the DefaultTimeout of a minute", deadline)
+	}
+	ctx.Cancel()
+
+	ctx = NewRequest("http://localhost", WithTimeout(time.Second)).context()
+	if deadline, ok := ctx.Ctx.Deadline(); !ok || time.Until(deadline) > time.Second {
+		t.Errorf("context() has deadline %v, expected WithTimeout to override DefaultTimeout", deadline)
+	}
+	ctx.Cancel()
+
+	DefaultTimeout = 0
+
+	if ctx = NewRequest("http://localhost").context(); ctx.Cancel != nil {
+		t.Error("context() returned a cancellable context, expected a zero DefaultTimeout to leave Send unbounded")
+	}
+}
@@ -0,0 +1,47 @@
+package room
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+)
+
+// NewCookieJar returns an in-memory jar following the domain, path and expiry rules of net/http/cookiejar.
+func NewCookieJar() http.CookieJar {
+	jar, _ := cookiejar.New(nil)
+
+	return jar
+}
+
+// WithCookieJar stores Set-Cookie responses in jar and sends its matching cookies with the request.
+// Share the same jar between requests to keep a session across them.
+func WithCookieJar(jar http.CookieJar) OptionRequest {
+	return func(request *Request) {
+		request.jar = jar
+	}
+}
+
+// Cookies parses every Set-Cookie header of the response.
+func (r Response) Cookies() []*http.Cookie {
+	if r.raw != nil {
+		return r.raw.Cookies()
+	}
+
+	if r.Header == nil || r.Header.Get("Set-Cookie") == "" {
+		return nil
+	}
+
+	return (&http.Response{Header: http.Header{"Set-Cookie": asHeader(r.Header).Values("Set-Cookie")}}).Cookies()
+}
+
+// Cookie returns the cookie set by the response under name, the last one when it was set several times.
+func (r Response) Cookie(name string) (*http.Cookie, bool) {
+	var found *http.Cookie
+
+	for _, cookie := range r.Cookies() {
+		if cookie.Name == name {
+			found = cookie
+		}
+	}
+
+	return found, found != nil
+}
@@ -0,0 +1,71 @@
+package room
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequest_SendWithCookieJar(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/"})
+		case "/me":
+			cookie, err := r.Cookie("session")
+			if err != nil || cookie.Value != "abc" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}
+	}))
+	defer server.Close()
+
+	jar := NewCookieJar()
+
+	if _, err := NewRequest(server.URL+"/login", WithCookieJar(jar)).Send(); err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+
+	response, _ := NewRequest(server.URL+"/me", WithCookieJar(jar)).Send()
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Send() returned status %d, expected the session cookie to be sent", response.StatusCode)
+	}
+
+	response, _ = NewRequest(server.URL + "/me").Send()
+	if response.StatusCode != http.StatusUnauthorized {
+		t.Error("Send() without a jar sent the session cookie")
+	}
+}
+
+func TestResponse_Cookies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/", HttpOnly: true})
+		http.SetCookie(w, &http.Cookie{Name: "theme", Value: "dark", MaxAge: 3600})
+	}))
+	defer server.Close()
+
+	response, err := NewRequest(server.URL).Send()
+	if err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+
+	if cookies := response.Cookies(); len(cookies) != 2 {
+		t.Fatalf("Cookies() returned %v, expected both Set-Cookie headers", cookies)
+	}
+
+	if cookie, ok := response.Cookie("session"); !ok || cookie.Value != "abc" || !cookie.HttpOnly || cookie.Path != "/" {
+		t.Errorf("Cookie(session) returned (%v, %v), expected the http-only session cookie", cookie, ok)
+	}
+	if cookie, ok := response.Cookie("theme"); !ok || cookie.MaxAge != 3600 {
+		t.Errorf("Cookie(theme) returned (%v, %v), expected the theme cookie", cookie, ok)
+	}
+	if _, ok := response.Cookie("missing"); ok {
+		t.Error("Cookie(missing) reported a cookie the response did not set")
+	}
+
+	built := newTestResponse("", "")
+	built.Header.Add("Set-Cookie", "id=7; Path=/")
+	if cookie, ok := built.Cookie("id"); !ok || cookie.Value != "7" {
+		t.Errorf("Cookie(id) returned (%v, %v) for a response built without an exchange", cookie, ok)
+	}
+}
@@ -0,0 +1,148 @@
+package room
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const redactedValue = "***"
+
+var sensitiveHeaders = []string{headerKeyAuthorization, "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+type curlOptions struct {
+	redact bool
+}
+
+type OptionCurl func(options *curlOptions)
+
+// WithCurlRedaction replaces the values of the Authorization, Proxy-Authorization, Cookie and Set-Cookie headers by ***.
+// A request sent WithRedaction is always exported redacted, its config applying on top of these headers.
+func WithCurlRedaction() OptionCurl {
+	return func(options *curlOptions) {
+		options.redact = true
+	}
+}
+
+// ToCurl renders the request as it would be sent as a curl command. Text bodies are inlined with --data-raw,
+// binary ones are left to be piped with --data-binary @- and a MultipartBody or MultipartForm is rendered as -F fields and files.
+// A ReaderBody is rewound, or kept in memory up to MaxBodyReplaySize, so the request can still be sent afterwards.
+func (r *Request) ToCurl(opts ...OptionCurl) (string, error) {
+	options := curlOptions{}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	source := r
+	multipartBody, isMultipart := r.BodyParser.(*MultipartBody)
+
+	if form, ok := r.BodyParser.(*MultipartForm); ok {
+		multipartBody, isMultipart = form.body, true
+	}
+
+	if isMultipart {
+		// Parsing would drain the file readers, the parts are rendered from the body instead.
+		withoutBody := *r
+		withoutBody.BodyParser = dumpBody{}
+		source = &withoutBody
+	}
+
+	req, err := source.request(context.Background())
+
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{"curl"}
+
+	if req.Method != http.MethodGet {
+		args = append(args, "-X", req.Method)
+	}
+
+	args = append(args, shellQuote(req.URL.String()))
+
+	redaction := r.redaction
+
+	if options.redact && redaction == nil {
+		redaction = &RedactionConfig{}
+	}
+
+	header := redaction.header(req.Header)
+
+	for _, name := range sortedHeaderNames(header) {
+		for _, value := range header[name] {
+			args = append(args, "-H", shellQuote(name+": "+value))
+		}
+	}
+
+	if isMultipart {
+		for _, part := range multipartBody.parts {
+			if part.path != "" {
+				args = append(args, "-F", shellQuote(part.name+"=@"+part.path))
+			} else if part.reader == nil {
+				args = append(args, "-F", shellQuote(part.name+"="+part.value))
+			} else {
+				args = append(args, "-F", shellQuote(part.name+"=@"+part.fileName))
+			}
+		}
+
+		return strings.Join(args, " "), nil
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+
+		if err != nil {
+			return "", fmt.Errorf("read request body: %w", err)
+		}
+
+		body = redaction.body(body)
+
+		if isText(body) {
+			args = append(args, "--data-raw", shellQuote(string(body)))
+		} else {
+			args = append(args, "--data-binary", "@-")
+		}
+	}
+
+	return strings.Join(args, " "), nil
+}
+
+func sortedHeaderNames(header http.Header) []string {
+	names := make([]string, 0, len(header))
+
+	for name := range header {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func isSensitiveHeader(name string) bool {
+	for _, sensitive := range sensitiveHeaders {
+		if strings.EqualFold(name, sensitive) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isText accepts valid UTF-8 without NUL bytes.
+func isText(data []byte) bool {
+	return utf8.Valid(data) && !bytes.ContainsRune(data, 0)
+}
+
+// shellQuote wraps value in single quotes, closing and escaping those it contains.
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
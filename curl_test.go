@@ -0,0 +1,54 @@
+package room
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRequest_ToCurl(t *testing.T) {
+	r := NewRequest("https://api.example.com/users",
+		WithMethod(POST),
+		WithQuery(NewMapQuery(map[string]any{"page": "2"})),
+		WithBody(JSONBody(map[string]string{"name": "O'Brien"})),
+		WithBearerToken("secret"),
+		WithCookies(&http.Cookie{Name: "session", Value: "abc"}),
+		WithUserAgent("room-test"))
+
+	command, err := r.ToCurl()
+	expected := `curl -X POST 'https://api.example.com/users?page=2' -H 'Authorization: Bearer secret' -H 'Content-Type: application/json' ` +
+		`-H 'Cookie: session=abc' -H 'User-Agent: room-test' --data-raw '{"name":"O'\''Brien"}` + "\n'"
+	if err != nil || command != expected {
+		t.Errorf("ToCurl() returned (%s, %v), expected %s", command, err, expected)
+	}
+
+	command, _ = r.ToCurl(WithCurlRedaction())
+	if strings.Contains(command, "secret") || strings.Contains(command, "abc") || !strings.Contains(command, "'Authorization: ***'") {
+		t.Errorf("ToCurl(WithCurlRedaction()) returned %s, expected the credentials redacted", command)
+	}
+}
+
+func TestRequest_ToCurlBodies(t *testing.T) {
+	command, _ := NewRequest("https://api.example.com/files", WithMethod(PUT), WithBody(ReaderBody(bytes.NewReader([]byte{0xff, 0x00}), "application/octet-stream"))).ToCurl()
+	if !strings.HasSuffix(command, "--data-binary @-") {
+		t.Errorf("ToCurl() returned %s for a binary body, expected --data-binary @-", command)
+	}
+
+	file := strings.NewReader("content")
+	body := NewMultipartBody().AddField("title", "report").AddFile("file", "report.pdf", file)
+
+	command, _ = NewRequest("https://api.example.com/upload", WithMethod(POST), WithBody(body), WithUserAgent("room-test")).ToCurl()
+	expected := `curl -X POST 'https://api.example.com/upload' -H 'User-Agent: room-test' -F 'title=report' -F 'file=@report.pdf'`
+	if command != expected || file.Len() != len("content") {
+		t.Errorf("ToCurl() returned %s for a multipart body, expected %s without reading the file", command, expected)
+	}
+
+	if command, _ = NewRequest("https://api.example.com", WithUserAgent("room-test")).ToCurl(); command != `curl 'https://api.example.com' -H 'User-Agent: room-test'` {
+		t.Errorf("ToCurl() returned %s for a GET without body", command)
+	}
+
+	if _, err := NewRequest("https://api.example.com/my users").ToCurl(); err == nil {
+		t.Error("ToCurl() did not return the URL error")
+	}
+}
@@ -0,0 +1,39 @@
+package room
+
+import (
+	"context"
+	"net"
+	"net/http"
+)
+
+// WithUnixSocket connects to the unix domain socket at path whatever the host of the URL is,
+// the host is then only sent in the Host header, e.g. http://docker/v1.43/containers/json for the Docker daemon.
+// Proxies do not apply to socket connections.
+func WithUnixSocket(path string) OptionRequest {
+	var dialer net.Dialer
+
+	return func(request *Request) {
+		request.addTransportOption(unixSocketOption{path: path}, func(transport *http.Transport) {
+			transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "unix", path)
+			}
+			transport.Proxy = nil
+		})
+	}
+}
+
+type unixSocketOption struct {
+	path string
+}
+
+// WithDialContext opens the connections of the request with fn, for custom name resolution, source addresses
+// or keep-alive settings of a net.Dialer. TLS is still negotiated by the transport on top of the returned connection.
+// A func cannot be compared, so the request gets a transport of its own rather than sharing one with the requests
+// applying the same options.
+func WithDialContext(fn func(ctx context.Context, network, addr string) (net.Conn, error)) OptionRequest {
+	return func(request *Request) {
+		request.addTransportOption(nil, func(transport *http.Transport) {
+			transport.DialContext = fn
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package room
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestRequest_SendWithUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "room.sock")
+
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets are not available: %v", err)
+	}
+
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Host + r.URL.Path))
+	})}
+	go func() { _ = server.Serve(listener) }()
+	defer server.Close()
+
+	response, err := NewRequest("http://docker/v1.43/containers/json", WithUnixSocket(socket)).Send()
+	if err != nil || string(response.Data) != "docker/v1.43/containers/json" {
+		t.Errorf("WithUnixSocket() Send() returned (%s, %v), expected docker/v1.43/containers/json", response.Data, err)
+	}
+}
+
+func TestRequest_SendWithDialContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Host))
+	}))
+	defer server.Close()
+
+	var dialer net.Dialer
+	var dialed []string
+
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed = append(dialed, addr)
+
+		return dialer.DialContext(ctx, network, server.Listener.Addr().String())
+	}
+
+	response, err := NewRequest("http://api.example.invalid:8080/users", WithDialContext(dial)).Send()
+	if err != nil || string(response.Data) != "api.example.invalid:8080" {
+		t.Errorf("WithDialContext() Send() returned (%s, %v), expected api.example.invalid:8080", response.Data, err)
+	}
+	if len(dialed) != 1 || dialed[0] != "api.example.invalid:8080" {
+		t.Errorf("WithDialContext() dialed %v, expected [api.example.invalid:8080]", dialed)
+	}
+}
@@ -0,0 +1,335 @@
+package room
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	headerKeyWWWAuthenticate = "WWW-Authenticate"
+	qopAuth                  = "auth"
+	qopAuthInt               = "auth-int"
+)
+
+// WithDigestAuth answers HTTP Digest challenges with username and password, see DigestMiddleware.
+func WithDigestAuth(username, password string) OptionRequest {
+	return WithMiddleware(DigestMiddleware(username, password))
+}
+
+// DigestMiddleware implements RFC 7616 Digest authentication with the MD5 and SHA-256 algorithms, their -sess variants
+// and the auth and auth-int qop. On a 401 carrying a Digest challenge the request is sent again with the computed
+// Authorization, unless its body cannot be replayed. The last challenge is kept to authorize the following requests
+// upfront, share the middleware with WithMiddlewareClient to reuse it across requests.
+func DigestMiddleware(username, password string) Middleware {
+	auth := &digestAuth{username: username, password: password}
+
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if authorization, ok, err := auth.authorize(req); err != nil {
+				return nil, err
+			} else if ok {
+				req.Header.Set(headerKeyAuthorization, authorization)
+			}
+
+			response, err := next(req)
+
+			if err != nil || response.StatusCode != http.StatusUnauthorized {
+				return response, err
+			}
+
+			challenge, ok := parseDigestChallenge(response.Header.Values(headerKeyWWWAuthenticate))
+
+			if !ok || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+				return response, nil
+			}
+
+			retry := req.Clone(req.Context())
+
+			if req.GetBody != nil {
+				if retry.Body, err = req.GetBody(); err != nil {
+					return response, nil
+				}
+			}
+
+			auth.setChallenge(challenge)
+
+			authorization, ok, err := auth.authorize(retry)
+
+			if err != nil || !ok {
+				closeReaders(retry.Body)
+
+				if err != nil {
+					discardBody(response)
+
+					return nil, err
+				}
+
+				return response, nil
+			}
+
+			discardBody(response)
+
+			retry.Header.Set(headerKeyAuthorization, authorization)
+
+			return next(retry)
+		}
+	}
+}
+
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       []string
+}
+
+type digestAuth struct {
+	mu        sync.Mutex
+	username  string
+	password  string
+	challenge *digestChallenge
+	nc        int
+}
+
+func (a *digestAuth) setChallenge(challenge digestChallenge) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.challenge, a.nc = &challenge, 0
+}
+
+// authorize computes the Authorization answering the current challenge, false without one or when the only qop offered
+// is auth-int and the body cannot be read again to be hashed. It fails when no cnonce can be generated.
+func (a *digestAuth) authorize(req *http.Request) (string, bool, error) {
+	a.mu.Lock()
+
+	if a.challenge == nil {
+		a.mu.Unlock()
+
+		return "", false, nil
+	}
+
+	challenge := *a.challenge
+	a.nc++
+	nc := a.nc
+
+	a.mu.Unlock()
+
+	newHash, ok := digestHash(challenge.algorithm)
+
+	if !ok {
+		return "", false, nil
+	}
+
+	h := func(s string) string {
+		sum := newHash()
+		sum.Write([]byte(s))
+
+		return hex.EncodeToString(sum.Sum(nil))
+	}
+
+	qop := ""
+
+	if slices.Contains(challenge.qop, qopAuth) {
+		qop = qopAuth
+	} else if slices.Contains(challenge.qop, qopAuthInt) {
+		qop = qopAuthInt
+	} else if len(challenge.qop) > 0 {
+		return "", false, nil
+	}
+
+	cnonce, err := newCnonce()
+
+	if err != nil {
+		return "", false, err
+	}
+
+	uri := req.URL.RequestURI()
+
+	ha1 := h(a.username + ":" + challenge.realm + ":" + a.password)
+
+	if strings.HasSuffix(strings.ToLower(challenge.algorithm), "-sess") {
+		ha1 = h(ha1 + ":" + challenge.nonce + ":" + cnonce)
+	}
+
+	ha2 := h(req.Method + ":" + uri)
+
+	if qop == qopAuthInt {
+		body, ok := replayBody(req)
+
+		if !ok {
+			return "", false, nil
+		}
+
+		ha2 = h(req.Method + ":" + uri + ":" + h(string(body)))
+	}
+
+	ncValue := fmt.Sprintf("%08x", nc)
+
+	var response string
+
+	if qop == "" {
+		response = h(ha1 + ":" + challenge.nonce + ":" + ha2)
+	} else {
+		response = h(ha1 + ":" + challenge.nonce + ":" + ncValue + ":" + cnonce + ":" + qop + ":" + ha2)
+	}
+
+	parts := []string{
+		fmt.Sprintf("username=%q", a.username),
+		fmt.Sprintf("realm=%q", challenge.realm),
+		fmt.Sprintf("nonce=%q", challenge.nonce),
+		fmt.Sprintf("uri=%q", uri),
+	}
+
+	if challenge.algorithm != "" {
+		parts = append(parts, "algorithm="+challenge.algorithm)
+	}
+
+	parts = append(parts, fmt.Sprintf("response=%q", response))
+
+	if challenge.opaque != "" {
+		parts = append(parts, fmt.Sprintf("opaque=%q", challenge.opaque))
+	}
+
+	if qop != "" {
+		parts = append(parts, "qop="+qop, "nc="+ncValue, fmt.Sprintf("cnonce=%q", cnonce))
+	}
+
+	return "Digest " + strings.Join(parts, ", "), true, nil
+}
+
+func digestHash(algorithm string) (func() hash.Hash, bool) {
+	switch strings.ToUpper(strings.TrimSuffix(strings.ToLower(algorithm), "-sess")) {
+	case "", "MD5":
+		return md5.New, true
+	case "SHA-256":
+		return sha256.New, true
+	default:
+		return nil, false
+	}
+}
+
+// replayBody reads a copy of the request body from GetBody, an empty body needs none.
+func replayBody(req *http.Request) ([]byte, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, true
+	}
+
+	if req.GetBody == nil {
+		return nil, false
+	}
+
+	body, err := req.GetBody()
+
+	if err != nil {
+		return nil, false
+	}
+
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+
+	return data, err == nil
+}
+
+func newCnonce() (string, error) {
+	var b [16]byte
+
+	if _, err := io.ReadFull(rand.Reader, b[:]); err != nil {
+		return "", fmt.Errorf("generate digest cnonce: %w", err)
+	}
+
+	return hex.EncodeToString(b[:]), nil
+}
+
+// parseDigestChallenge finds the Digest challenge among the WWW-Authenticate values.
+// A value offering several schemes is only split on the Digest keyword.
+func parseDigestChallenge(values []string) (digestChallenge, bool) {
+	for _, value := range values {
+		index := strings.Index(strings.ToLower(value), "digest ")
+
+		if index < 0 || (index > 0 && value[index-1] != ' ' && value[index-1] != ',') {
+			continue
+		}
+
+		params := parseAuthParams(value[index+len("digest "):])
+
+		if params["nonce"] == "" {
+			continue
+		}
+
+		challenge := digestChallenge{
+			realm:     params["realm"],
+			nonce:     params["nonce"],
+			opaque:    params["opaque"],
+			algorithm: params["algorithm"],
+		}
+
+		for _, qop := range strings.Split(params["qop"], ",") {
+			if qop = strings.TrimSpace(qop); qop != "" {
+				challenge.qop = append(challenge.qop, qop)
+			}
+		}
+
+		return challenge, true
+	}
+
+	return digestChallenge{}, false
+}
+
+// parseAuthParams parses comma separated key=value and key="quoted value" pairs, keys lower-cased.
+// It stops at a token without "=", the start of the next challenge.
+func parseAuthParams(s string) map[string]string {
+	params := map[string]string{}
+
+	for {
+		s = strings.TrimLeft(s, " ,")
+
+		eq := strings.IndexByte(s, '=')
+
+		if eq <= 0 || strings.ContainsAny(s[:eq], " ,") {
+			return params
+		}
+
+		key := strings.ToLower(strings.TrimSpace(s[:eq]))
+		s = strings.TrimLeft(s[eq+1:], " ")
+
+		var value string
+
+		if strings.HasPrefix(s, `"`) {
+			var b strings.Builder
+
+			i := 1
+
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+
+				b.WriteByte(s[i])
+			}
+
+			value, s = b.String(), s[min(i+1, len(s)):]
+		} else {
+			end := strings.IndexByte(s, ',')
+
+			if end < 0 {
+				end = len(s)
+			}
+
+			value, s = strings.TrimSpace(s[:end]), s[end:]
+		}
+
+		params[key] = value
+	}
+}
@@ -0,0 +1,112 @@
+package room
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// digestServer accepts user:secret answering a challenge with the given qop, counting the challenges it sent.
+func digestServer(t *testing.T, qop string, challenges *int) *httptest.Server {
+	t.Helper()
+
+	const realm, nonce = "api@example.com", "dcd98b7102dd2f0e8b11d0f600bfb0c093"
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := parseAuthParams(strings.TrimPrefix(r.Header.Get(headerKeyAuthorization), "Digest "))
+		body, _ := io.ReadAll(r.Body)
+
+		ha1 := md5Hex("user:" + realm + ":secret")
+		ha2 := md5Hex(r.Method + ":" + r.URL.RequestURI())
+
+		if qop == qopAuthInt {
+			ha2 = md5Hex(r.Method + ":" + r.URL.RequestURI() + ":" + md5Hex(string(body)))
+		}
+
+		expected := md5Hex(ha1 + ":" + nonce + ":" + params["nc"] + ":" + params["cnonce"] + ":" + qop + ":" + ha2)
+
+		if params["response"] != expected || params["qop"] != qop || params["uri"] != r.URL.RequestURI() || params["opaque"] != "5ccc069c" {
+			*challenges++
+			w.Header().Add(headerKeyWWWAuthenticate, `Basic realm="fallback"`)
+			w.Header().Add(headerKeyWWWAuthenticate, `Digest realm="`+realm+`", qop="`+qop+`", nonce="`+nonce+`", opaque="5ccc069c"`)
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		_, _ = w.Write([]byte("welcome " + string(body)))
+	}))
+}
+
+func TestRequest_SendWithDigestAuth(t *testing.T) {
+	for _, qop := range []string{qopAuth, qopAuthInt} {
+		var challenges int
+
+		server := digestServer(t, qop, &challenges)
+
+		response, err := NewRequest(server.URL+"/dir/index.html?page=1", WithMethod(POST), WithBody(JSONBody("payload")),
+			WithDigestAuth("user", "secret")).Send()
+
+		if err != nil || response.StatusCode != http.StatusOK || !strings.HasPrefix(string(response.Data), `welcome "payload"`) {
+			t.Errorf("Send() with qop %s returned (%d %s, %v), expected to be authorized", qop, response.StatusCode, response.Data, err)
+		}
+		if challenges != 1 {
+			t.Errorf("Send() with qop %s was challenged %d times, expected 1", qop, challenges)
+		}
+
+		response, _ = NewRequest(server.URL, WithDigestAuth("user", "wrong")).Send()
+		if response.StatusCode != http.StatusUnauthorized || challenges != 3 {
+			t.Errorf("Send() with a wrong password returned %d after %d challenges, expected 401 after 3", response.StatusCode, challenges)
+		}
+
+		server.Close()
+	}
+}
+
+func TestRequest_SendWithDigestAuthRandomFailure(t *testing.T) {
+	var challenges int
+
+	server := digestServer(t, qopAuth, &challenges)
+	defer server.Close()
+
+	defer func(reader io.Reader) { rand.Reader = reader }(rand.Reader)
+
+	errEntropy := errors.New("no entropy")
+	rand.Reader = iotest.ErrReader(errEntropy)
+
+	if _, err := NewRequest(server.URL, WithDigestAuth("user", "secret")).Send(); !errors.Is(err, errEntropy) {
+		t.Errorf("Send() returned %v, expected the error of the random source", err)
+	}
+}
+
+func TestDigestMiddleware_ReusesChallenge(t *testing.T) {
+	var challenges int
+
+	server := digestServer(t, qopAuth, &challenges)
+	defer server.Close()
+
+	client := NewClient(server.URL, WithMiddlewareClient(DigestMiddleware("user", "secret")))
+
+	for i := 0; i < 3; i++ {
+		if response, err := client.Get("/").Send(); err != nil || response.StatusCode != http.StatusOK {
+			t.Fatalf("Send() returned (%d, %v), expected to be authorized", response.StatusCode, err)
+		}
+	}
+
+	if challenges != 1 {
+		t.Errorf("Send() was challenged %d times, expected the challenge to be reused", challenges)
+	}
+}
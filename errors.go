@@ -0,0 +1,94 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// RequestError is the error returned by Send when the request could not be built, sent or read.
+// Method and URL are empty when the request failed before it was built.
+type RequestError struct {
+	Method string
+	URL    string
+	Err    error
+}
+
+// newRequestError wraps err once, an error already wrapped is returned as-is.
+func newRequestError(request *http.Request, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var requestErr *RequestError
+
+	if errors.As(err, &requestErr) {
+		return err
+	}
+
+	requestErr = &RequestError{Err: err}
+
+	if request != nil {
+		requestErr.Method = request.Method
+		requestErr.URL = request.URL.String()
+	}
+
+	return requestErr
+}
+
+func (e *RequestError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+func (e *RequestError) Timeout() bool {
+	return IsTimeout(e.Err)
+}
+
+func (e *RequestError) Canceled() bool {
+	return IsCanceled(e.Err)
+}
+
+func (e *RequestError) ConnectionError() bool {
+	return IsConnectionError(e.Err)
+}
+
+// IsTimeout reports whether err comes from an expired deadline, of the context or of the connection.
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// IsCanceled reports whether err comes from a cancelled context.
+func IsCanceled(err error) bool {
+	return errors.Is(err, context.Canceled)
+}
+
+// IsConnectionError reports whether err comes from the network, such as a refused or reset connection
+// or a failed DNS lookup. Timeouts and cancellations are reported by IsTimeout and IsCanceled instead.
+func IsConnectionError(err error) bool {
+	if err == nil || IsTimeout(err) || IsCanceled(err) {
+		return false
+	}
+
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+
+	return errors.As(err, &opErr) ||
+		errors.As(err, &dnsErr) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
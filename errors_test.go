@@ -0,0 +1,48 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequestError_Classification(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	_, err := NewRequest(server.URL, WithTimeout(20*time.Millisecond)).Send()
+	var requestErr *RequestError
+	if !errors.As(err, &requestErr) || !requestErr.Timeout() || requestErr.Canceled() || requestErr.ConnectionError() {
+		t.Errorf("Send() returned %v, expected a timeout *RequestError", err)
+	}
+	if requestErr != nil && (requestErr.Method != "GET" || requestErr.URL != server.URL) {
+		t.Errorf("RequestError carried %s %s, expected GET %s", requestErr.Method, requestErr.URL, server.URL)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = NewRequest(server.URL).SendWithContext(ctx); !IsCanceled(err) || IsTimeout(err) || IsConnectionError(err) {
+		t.Errorf("SendWithContext() returned %v, expected a cancellation", err)
+	}
+
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	address := listener.Addr().String()
+	_ = listener.Close()
+
+	if _, err = NewRequest("http://" + address).Send(); !IsConnectionError(err) || IsTimeout(err) || IsCanceled(err) {
+		t.Errorf("Send() returned %v, expected a connection error", err)
+	}
+
+	if _, err = NewRequest("http://localhost/my users").Send(); !errors.As(err, &requestErr) || IsConnectionError(err) {
+		t.Errorf("Send() returned %v, expected an unclassified *RequestError", err)
+	}
+}
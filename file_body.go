@@ -0,0 +1,112 @@
+package room
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+)
+
+// sniffSize is the number of bytes http.DetectContentType considers.
+const sniffSize = 512
+
+type fileBody struct {
+	path        string
+	contentType string
+}
+
+// FileBody streams the file at path as the request body with a known Content-Length. The content type is derived
+// from the extension, or sniffed from the first bytes of the file when the extension is unknown.
+// The file is opened on every attempt and closed once read to the end, or when the request is done with it.
+func FileBody(path string) (IBodyParser, error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	info, err := file.Stat()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("file body %s: not a regular file", path)
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(path))
+
+	if contentType == "" {
+		head := make([]byte, sniffSize)
+		n, err := io.ReadFull(file, head)
+
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return nil, err
+		}
+
+		contentType = http.DetectContentType(head[:n])
+	}
+
+	return &fileBody{path: path, contentType: contentType}, nil
+}
+
+func (f *fileBody) Parse() (io.Reader, error) {
+	file, err := os.Open(f.path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+
+	if err != nil {
+		_ = file.Close()
+
+		return nil, err
+	}
+
+	return &fileReader{file: file, remaining: info.Size()}, nil
+}
+
+func (f *fileBody) ContentType() string { return f.contentType }
+
+// fileReader closes the file at EOF, so it is released even when the body is wrapped by readers that do not close,
+// such as WithGzipBody or upload progress. Len reports the remaining size for the Content-Length.
+type fileReader struct {
+	file      *os.File
+	remaining int64
+	closed    atomic.Bool
+}
+
+func (f *fileReader) Read(p []byte) (int, error) {
+	if f.closed.Load() {
+		return 0, io.EOF
+	}
+
+	n, err := f.file.Read(p)
+	f.remaining -= int64(n)
+
+	if err == io.EOF {
+		_ = f.Close()
+	}
+
+	return n, err
+}
+
+func (f *fileReader) Len() int {
+	return int(max(f.remaining, 0))
+}
+
+func (f *fileReader) Close() error {
+	if f.closed.Swap(true) {
+		return nil
+	}
+
+	return f.file.Close()
+}
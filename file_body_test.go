@@ -0,0 +1,71 @@
+package room
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileBody(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "payload.json")
+	rawPath := filepath.Join(dir, "payload")
+
+	_ = os.WriteFile(jsonPath, []byte(`{"key":"value"}`), 0o600)
+	_ = os.WriteFile(rawPath, []byte("plain text content"), 0o600)
+
+	bodyParser, err := FileBody(jsonPath)
+	if err != nil {
+		t.Fatalf("FileBody() returned unexpected error: %v", err)
+	}
+	if bodyParser.ContentType() != "application/json" {
+		t.Errorf("FileBody() ContentType() returned %s, expected application/json", bodyParser.ContentType())
+	}
+
+	bodyParser, _ = FileBody(rawPath)
+	if bodyParser.ContentType() != "text/plain; charset=utf-8" {
+		t.Errorf("FileBody() ContentType() returned %s, expected text/plain; charset=utf-8", bodyParser.ContentType())
+	}
+
+	reader, _ := bodyParser.Parse()
+	if body, _ := io.ReadAll(reader); string(body) != "plain text content" {
+		t.Errorf("FileBody() Parse() returned %s, expected plain text content", body)
+	}
+	if file := reader.(*fileReader).file; file.Close() == nil {
+		t.Error("FileBody() did not close the file once read to the end")
+	}
+
+	if _, err = FileBody(filepath.Join(dir, "missing")); err == nil {
+		t.Error("FileBody() did not return an error for a missing file")
+	}
+	if _, err = FileBody(dir); err == nil {
+		t.Error("FileBody() did not return an error for a directory")
+	}
+}
+
+func TestRequest_SendWithFileBody(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "upload.txt")
+	_ = os.WriteFile(path, []byte("file content"), 0o600)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		if r.ContentLength != 12 || len(r.TransferEncoding) > 0 || string(body) != "file content" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	bodyParser, _ := FileBody(path)
+
+	response, err := NewRequest(server.URL, WithMethod(POST), WithBody(bodyParser)).Send()
+	if err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Send() returned status %d, expected the file streamed with its Content-Length", response.StatusCode)
+	}
+}
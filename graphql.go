@@ -0,0 +1,90 @@
+package room
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type graphQLRequest struct {
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	OperationName string         `json:"operationName,omitempty"`
+}
+
+// GraphQLBody sends the standard {query, variables, operationName} envelope as JSON,
+// empty variables and operation name are left out.
+func GraphQLBody(query string, variables map[string]any, operationName string) IBodyParser {
+	return JSONBody(graphQLRequest{Query: query, Variables: variables, OperationName: operationName})
+}
+
+type GraphQLLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// GraphQLError is an entry of the errors array of a GraphQL response.
+type GraphQLError struct {
+	Message    string            `json:"message"`
+	Path       []any             `json:"path,omitempty"`
+	Locations  []GraphQLLocation `json:"locations,omitempty"`
+	Extensions map[string]any    `json:"extensions,omitempty"`
+}
+
+func (e GraphQLError) Error() string {
+	if len(e.Path) == 0 {
+		return e.Message
+	}
+
+	path := make([]string, 0, len(e.Path))
+
+	for _, segment := range e.Path {
+		path = append(path, fmt.Sprint(segment))
+	}
+
+	return strings.Join(path, ".") + ": " + e.Message
+}
+
+// GraphQLErrors is returned by Response.GraphQL when the server reported errors.
+type GraphQLErrors []GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	messages := make([]string, 0, len(e))
+
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return "graphql: " + strings.Join(messages, "; ")
+}
+
+// GraphQL unmarshals the data field of a GraphQL response into data. Errors reported by the server are returned
+// as GraphQLErrors, data still holds what the server resolved when the response is partial.
+func (r Response) GraphQL(data any) error {
+	body, err := r.decodableBytes(jsonMediaTypes, isJSONMediaType)
+
+	if err != nil {
+		return err
+	}
+
+	var envelope struct {
+		Data   json.RawMessage `json:"data"`
+		Errors GraphQLErrors   `json:"errors"`
+	}
+
+	if err = json.Unmarshal(body, &envelope); err != nil {
+		return fmt.Errorf("decode graphql response: %w", err)
+	}
+
+	if len(envelope.Data) > 0 && string(envelope.Data) != "null" && data != nil {
+		if err = json.Unmarshal(envelope.Data, data); err != nil {
+			return fmt.Errorf("decode graphql data: %w", err)
+		}
+	}
+
+	if len(envelope.Errors) > 0 {
+		return envelope.Errors
+	}
+
+	return nil
+}
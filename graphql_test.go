@@ -0,0 +1,42 @@
+package room
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGraphQLBody(t *testing.T) {
+	body := GraphQLBody("query User($id: ID!) { user(id: $id) { name } }", map[string]any{"id": 1}, "User")
+
+	expected := `{"query":"query User($id: ID!) { user(id: $id) { name } }","variables":{"id":1},"operationName":"User"}`
+	if result := strings.TrimSpace(parseString(t, body)); result != expected || body.ContentType() != headerValueApplicationJson {
+		t.Errorf("GraphQLBody() returned %s (%s), expected %s", result, body.ContentType(), expected)
+	}
+
+	if result := strings.TrimSpace(parseString(t, GraphQLBody("{ me { name } }", nil, ""))); result != `{"query":"{ me { name } }"}` {
+		t.Errorf("GraphQLBody() returned %s, expected the optional fields left out", result)
+	}
+}
+
+func TestResponse_GraphQL(t *testing.T) {
+	var data struct {
+		User struct{ Name string }
+	}
+
+	response := newTestResponse("application/json", `{"data":{"user":{"name":"room"}}}`)
+	if err := response.GraphQL(&data); err != nil || data.User.Name != "room" {
+		t.Errorf("GraphQL() returned (%+v, %v), expected the user", data, err)
+	}
+
+	response = newTestResponse("application/json", `{"data":{"user":{"name":"partial"}},"errors":[{"message":"not allowed","path":["user","email"]}]}`)
+	err := response.GraphQL(&data)
+
+	var graphQLErrors GraphQLErrors
+	if !errors.As(err, &graphQLErrors) || len(graphQLErrors) != 1 || data.User.Name != "partial" {
+		t.Fatalf("GraphQL() returned (%+v, %v), expected the partial data and the errors", data, err)
+	}
+	if err.Error() != "graphql: user.email: not allowed" {
+		t.Errorf("GraphQL() returned error %q, expected graphql: user.email: not allowed", err.Error())
+	}
+}
@@ -0,0 +1,39 @@
+//go:build go1.24
+
+// Package h2c sends room requests to http:// URLs over HTTP/2 without TLS, prior knowledge h2c, as gRPC backends and
+// plaintext service meshes expect. It relies on http.Protocols, so it needs a Go 1.24 toolchain and no extra dependency,
+// older toolchains build a WithH2C failing every request with errors.ErrUnsupported.
+package h2c
+
+import (
+	"net/http"
+
+	"github.com/WEG-Technology/room"
+)
+
+// transport is shared by the requests using WithH2C so they multiplex over the same connections.
+var transport = newTransport()
+
+func newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	Configure(transport)
+
+	return transport
+}
+
+// WithH2C sends the request over HTTP/2, unencrypted for http:// URLs and over TLS for https:// ones, HTTP/1.1 is not spoken.
+// Transport options such as room.WithTLSConfig apply to a copy of the shared h2c transport.
+func WithH2C() room.OptionRequest {
+	return room.WithTransport(transport)
+}
+
+// Configure switches transport to h2c, room.ConfigureTransport(h2c.Configure) does it for every request on the shared client.
+// HTTP/1.1 is turned off, the transport would otherwise prefer it over unencrypted HTTP/2.
+func Configure(transport *http.Transport) {
+	var protocols http.Protocols
+
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+
+	transport.Protocols = &protocols
+}
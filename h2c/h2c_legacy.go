@@ -0,0 +1,22 @@
+//go:build !go1.24
+
+package h2c
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/WEG-Technology/room"
+)
+
+// WithH2C fails the request with errors.ErrUnsupported, h2c needs http.Protocols from Go 1.24.
+func WithH2C() room.OptionRequest {
+	return room.WithTransport(unsupported{})
+}
+
+type unsupported struct{}
+
+func (unsupported) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf("h2c needs Go 1.24: %w", errors.ErrUnsupported)
+}
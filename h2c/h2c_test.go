@@ -0,0 +1,35 @@
+//go:build go1.24
+
+package h2c
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/WEG-Technology/room"
+)
+
+func TestWithH2C(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Proto))
+	}))
+
+	var protocols http.Protocols
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+	server.Config.Protocols = &protocols
+
+	server.Start()
+	defer server.Close()
+
+	response, err := room.NewRequest(server.URL).Send()
+	if err != nil || string(response.Data) != "HTTP/1.1" {
+		t.Errorf("Send() returned (%s, %v), expected HTTP/1.1 without WithH2C", response.Data, err)
+	}
+
+	response, err = room.NewRequest(server.URL, WithH2C()).Send()
+	if err != nil || string(response.Data) != "HTTP/2.0" {
+		t.Errorf("WithH2C() Send() returned (%s, %v), expected HTTP/2.0", response.Data, err)
+	}
+}
@@ -0,0 +1,260 @@
+package room
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+)
+
+var ErrNoExchange = errors.New("response holds no HTTP exchange")
+
+type harLog struct {
+	Log harContent `json:"log"`
+}
+
+type harContent struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harCookie    `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harCookie    `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harBody        `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harCookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Path     string `json:"path,omitempty"`
+	Domain   string `json:"domain,omitempty"`
+	Expires  string `json:"expires,omitempty"`
+	HTTPOnly bool   `json:"httpOnly,omitempty"`
+	Secure   bool   `json:"secure,omitempty"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harBody struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	SSL     float64 `json:"ssl"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// ToHAR exports the request and the response as a HAR 1.2 log holding a single entry, timed with Response.Timings.
+// Bodies that are not UTF-8 text are base64 encoded. A body left unread by WithStream is read by the export.
+// The sensitive headers and cookies are redacted, along with the headers and JSON fields of a WithRedaction config.
+func (r Response) ToHAR() ([]byte, error) {
+	if r.raw == nil || r.raw.Request == nil {
+		return nil, ErrNoExchange
+	}
+
+	body, err := r.Bytes()
+
+	if err != nil {
+		return nil, err
+	}
+
+	req := r.raw.Request
+	timings := r.timings
+	redaction := redactionFrom(req.Context())
+	body = redaction.body(body)
+	requestData := redaction.body(r.Request.Data)
+
+	entry := harEntry{
+		StartedDateTime: r.startedAt.Format(time.RFC3339Nano),
+		Time:            milliseconds(timings.Total),
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         req.URL.String(),
+			HTTPVersion: harProto(req.Proto),
+			Cookies:     harCookies(req.Cookies(), redaction.isSensitive("Cookie")),
+			Headers:     harHeaders(redaction.header(req.Header)),
+			QueryString: harQuery(req),
+			HeadersSize: -1,
+			BodySize:    len(requestData),
+		},
+		Response: harResponse{
+			Status:      r.raw.StatusCode,
+			StatusText:  http.StatusText(r.raw.StatusCode),
+			HTTPVersion: harProto(r.raw.Proto),
+			Cookies:     harCookies(r.raw.Cookies(), redaction.isSensitive("Set-Cookie")),
+			Headers:     harHeaders(redaction.header(r.raw.Header)),
+			Content:     harContentBody(body, r.raw.Header.Get(headerKeyContentType)),
+			RedirectURL: r.raw.Header.Get("Location"),
+			HeadersSize: -1,
+			BodySize:    len(body),
+		},
+		Timings: harTimings{
+			Blocked: -1,
+			DNS:     optionalMilliseconds(timings.DNS),
+			Connect: optionalMilliseconds(timings.Connect + timings.TLSHandshake),
+			SSL:     optionalMilliseconds(timings.TLSHandshake),
+			Wait:    milliseconds(max(timings.FirstByte-timings.DNS-timings.Connect-timings.TLSHandshake, 0)),
+			Receive: milliseconds(max(timings.Total-timings.FirstByte, 0)),
+		},
+	}
+
+	if len(requestData) > 0 {
+		postData := &harPostData{MimeType: req.Header.Get(headerKeyContentType), Text: string(requestData)}
+
+		if !isText(requestData) {
+			postData.Text, postData.Encoding = base64.StdEncoding.EncodeToString(requestData), "base64"
+		}
+
+		entry.Request.PostData = postData
+	}
+
+	return json.Marshal(harLog{Log: harContent{
+		Version: "1.2",
+		Creator: harCreator{Name: "room", Version: Version},
+		Entries: []harEntry{entry},
+	}})
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// optionalMilliseconds reports a phase that did not happen, on a reused connection for instance, as -1.
+func optionalMilliseconds(d time.Duration) float64 {
+	if d == 0 {
+		return -1
+	}
+
+	return milliseconds(d)
+}
+
+func harProto(proto string) string {
+	if proto == "" {
+		return "HTTP/1.1"
+	}
+
+	return proto
+}
+
+func harHeaders(header http.Header) []harNameValue {
+	pairs := []harNameValue{}
+
+	for _, name := range sortedHeaderNames(header) {
+		for _, value := range header[name] {
+			pairs = append(pairs, harNameValue{Name: name, Value: value})
+		}
+	}
+
+	return pairs
+}
+
+func harQuery(req *http.Request) []harNameValue {
+	query := req.URL.Query()
+	names := make([]string, 0, len(query))
+
+	for name := range query {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	pairs := []harNameValue{}
+
+	for _, name := range names {
+		for _, value := range query[name] {
+			pairs = append(pairs, harNameValue{Name: name, Value: value})
+		}
+	}
+
+	return pairs
+}
+
+func harCookies(cookies []*http.Cookie, redact bool) []harCookie {
+	entries := []harCookie{}
+
+	for _, cookie := range cookies {
+		entry := harCookie{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Path:     cookie.Path,
+			Domain:   cookie.Domain,
+			HTTPOnly: cookie.HttpOnly,
+			Secure:   cookie.Secure,
+		}
+
+		if redact {
+			entry.Value = redactedValue
+		}
+
+		if !cookie.Expires.IsZero() {
+			entry.Expires = cookie.Expires.UTC().Format(time.RFC3339)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+func harContentBody(body []byte, contentType string) harBody {
+	content := harBody{Size: len(body), MimeType: contentType, Text: string(body)}
+
+	if !isText(body) {
+		content.Text, content.Encoding = base64.StdEncoding.EncodeToString(body), "base64"
+	}
+
+	return content
+}
@@ -0,0 +1,73 @@
+package room
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResponse_ToHAR(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", HttpOnly: true})
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte{0xff, 0x00})
+	}))
+	defer server.Close()
+
+	response, err := NewRequest(server.URL+"/users",
+		WithMethod(POST),
+		WithQuery(NewMapQuery(map[string]any{"page": "2"})),
+		WithBody(JSONBody(map[string]string{"name": "room"})),
+		WithCookies(&http.Cookie{Name: "theme", Value: "dark"})).Send()
+	if err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+
+	data, err := response.ToHAR()
+	if err != nil {
+		t.Fatalf("ToHAR() returned unexpected error: %v", err)
+	}
+
+	var har harLog
+	if err = json.Unmarshal(data, &har); err != nil || har.Log.Version != "1.2" || len(har.Log.Entries) != 1 {
+		t.Fatalf("ToHAR() returned %s, expected a HAR 1.2 log with one entry", data)
+	}
+
+	entry := har.Log.Entries[0]
+	if entry.Request.Method != "POST" || entry.Request.URL != server.URL+"/users?page=2" {
+		t.Errorf("ToHAR() exported request %s %s", entry.Request.Method, entry.Request.URL)
+	}
+	if len(entry.Request.QueryString) != 1 || entry.Request.QueryString[0] != (harNameValue{Name: "page", Value: "2"}) {
+		t.Errorf("ToHAR() exported query %v, expected page=2", entry.Request.QueryString)
+	}
+	if len(entry.Request.Cookies) != 1 || entry.Request.Cookies[0].Name != "theme" || entry.Request.Cookies[0].Value != redactedValue {
+		t.Errorf("ToHAR() exported request cookies %v, expected theme redacted", entry.Request.Cookies)
+	}
+	for _, header := range append(entry.Request.Headers, entry.Response.Headers...) {
+		if isSensitiveHeader(header.Name) && header.Value != redactedValue {
+			t.Errorf("ToHAR() exported header %s: %s, expected it redacted", header.Name, header.Value)
+		}
+	}
+	if entry.Request.PostData == nil || entry.Request.PostData.Text != "{\"name\":\"room\"}\n" || entry.Request.PostData.MimeType != "application/json" {
+		t.Errorf("ToHAR() exported post data %+v, expected the JSON body", entry.Request.PostData)
+	}
+	if entry.Response.Status != http.StatusCreated || entry.Response.StatusText != "Created" {
+		t.Errorf("ToHAR() exported status %d %s, expected 201 Created", entry.Response.Status, entry.Response.StatusText)
+	}
+	if len(entry.Response.Cookies) != 1 || !entry.Response.Cookies[0].HTTPOnly || entry.Response.Cookies[0].Value != redactedValue {
+		t.Errorf("ToHAR() exported response cookies %v, expected the http-only session redacted", entry.Response.Cookies)
+	}
+	if entry.Response.Content.Encoding != "base64" || entry.Response.Content.Text != "/wA=" || entry.Response.Content.Size != 2 {
+		t.Errorf("ToHAR() exported content %+v, expected the binary body base64 encoded", entry.Response.Content)
+	}
+	if entry.Time <= 0 || entry.StartedDateTime == "" {
+		t.Errorf("ToHAR() exported time %v started at %s, expected the timing of the exchange", entry.Time, entry.StartedDateTime)
+	}
+
+	if _, err = (Response{}).ToHAR(); !errors.Is(err, ErrNoExchange) {
+		t.Errorf("ToHAR() returned %v for an empty response, expected ErrNoExchange", err)
+	}
+}
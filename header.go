@@ -1,6 +1,12 @@
 package room
 
-import "github.com/WEG-Technology/room/store"
+import (
+	"fmt"
+	"github.com/WEG-Technology/room/store"
+	"slices"
+	"sort"
+	"strings"
+)
 
 type IHeader interface {
 	Properties() store.IMap
@@ -10,24 +16,58 @@ type IHeader interface {
 	String() string
 }
 
+// Header holds a string per key, or a []string once values were appended to a key.
 type Header struct {
 	properties store.IMap
 }
 
+// Add replaces the values of key by value. Use Append to send several values under the same key.
 func (h *Header) Add(key string, value string) IHeader {
 	h.properties.Add(key, value)
 
 	return h
 }
 
+// Append adds value to the values of key, each value is sent as a header line of its own.
+func (h *Header) Append(key string, value string) *Header {
+	if existing, ok := h.properties.GetItem(key); ok {
+		h.properties.Add(key, append(slices.Clip(headerValues(existing)), value))
+	} else {
+		h.properties.Add(key, value)
+	}
+
+	return h
+}
+
+// Get returns the first value of key.
 func (h *Header) Get(key string) string {
-	s, ok := h.properties.GetItem(key)
+	if values := h.Values(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+func (h *Header) Values(key string) []string {
+	value, ok := h.properties.GetItem(key)
 
 	if !ok {
-		return ""
+		return nil
 	}
 
-	return s.(string)
+	return slices.Clone(headerValues(value))
+}
+
+// headerValues reads a value stored with Properties().Add, which accepts any type, numbers and booleans included.
+func headerValues(value any) []string {
+	switch value := value.(type) {
+	case string:
+		return []string{value}
+	case []string:
+		return value
+	default:
+		return []string{fmt.Sprint(value)}
+	}
 }
 
 func (h *Header) Merge(header IHeader) IHeader {
@@ -38,6 +78,38 @@ func (h *Header) Merge(header IHeader) IHeader {
 	return h
 }
 
+// Each calls fn with every key and value of the header, once per value of a repeated key, keys in sorted order.
+func (h *Header) Each(fn func(key, value string)) {
+	for _, key := range h.keys() {
+		value, _ := h.properties.GetItem(key)
+
+		for _, v := range headerValues(value) {
+			fn(key, v)
+		}
+	}
+}
+
+func (h *Header) keys() []string {
+	keys := make([]string, 0, len(h.properties.All()))
+
+	h.properties.Each(func(key string, _ any) {
+		keys = append(keys, key)
+	})
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// asHeader reads any IHeader through the methods of Header, which IHeader does not require.
+func asHeader(header IHeader) *Header {
+	if h, ok := header.(*Header); ok {
+		return h
+	}
+
+	return &Header{header.Properties()}
+}
+
 func (h *Header) Properties() store.IMap {
 	return h.properties
 }
@@ -53,3 +125,64 @@ func NewHeader(properties ...store.IMap) IHeader {
 
 	return &Header{properties[0]}
 }
+
+// Headers starts an empty header to build with chained calls, e.g.
+// Headers().ContentType("application/json").Authorization("Bearer token").Set("X-Tenant", "acme").
+// Add returns an IHeader as the interface requires, so it ends a chain.
+func Headers() *Header {
+	return &Header{store.NewMapStore()}
+}
+
+// Set replaces the value of key, including one stored under a different case.
+func (h *Header) Set(key, value string) *Header {
+	h.Del(key)
+	h.properties.Add(key, value)
+
+	return h
+}
+
+// Del removes key whatever its case.
+func (h *Header) Del(key string) *Header {
+	h.properties.Each(func(existing string, _ any) {
+		if strings.EqualFold(existing, key) {
+			h.properties.Remove(existing)
+		}
+	})
+
+	return h
+}
+
+func (h *Header) ContentType(contentType string) *Header {
+	return h.Set(headerKeyContentType, contentType)
+}
+
+// Authorization sets the Authorization header to value as-is, e.g. "Bearer token", see WithBearerToken and WithBasicAuth.
+func (h *Header) Authorization(value string) *Header {
+	return h.Set(headerKeyAuthorization, value)
+}
+
+// overrideHeader returns a new header holding defaults overridden by header, keys being compared case-insensitively.
+// Neither argument is modified.
+func overrideHeader(defaults, header IHeader) IHeader {
+	if defaults == nil {
+		return header
+	}
+
+	merged := NewHeader().Merge(defaults)
+
+	if header == nil {
+		return merged
+	}
+
+	header.Properties().Each(func(key string, value any) {
+		merged.Properties().Each(func(defaultKey string, _ any) {
+			if defaultKey != key && strings.EqualFold(defaultKey, key) {
+				merged.Properties().Remove(defaultKey)
+			}
+		})
+
+		merged.Properties().Add(key, value)
+	})
+
+	return merged
+}
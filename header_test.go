@@ -2,6 +2,9 @@ package room
 
 import (
 	"github.com/WEG-Technology/room/store"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -71,3 +74,95 @@ func TestNewHeaderWithIMap(t *testing.T) {
 		t.Error("NewHeader() returned nil")
 	}
 }
+
+func TestHeaders(t *testing.T) {
+	h := Headers().
+		Set("x-tenant", "acme").
+		Set("X-Tenant", "globex").
+		ContentType("application/json").
+		Authorization("Bearer token").
+		Set("X-Debug", "1").
+		Del("x-debug")
+
+	var header IHeader = h.Add("X-Request-Id", "42")
+
+	expected := map[string]string{
+		"X-Tenant":      "globex",
+		"Content-Type":  "application/json",
+		"Authorization": "Bearer token",
+		"X-Request-Id":  "42",
+	}
+
+	seen := map[string]string{}
+	h.Each(func(key, value string) {
+		seen[key] = value
+	})
+
+	if len(seen) != len(expected) {
+		t.Errorf("Headers() built %v, expected %v", seen, expected)
+	}
+	for key, value := range expected {
+		if seen[key] != value {
+			t.Errorf("Headers() built %s: %s, expected %s", key, seen[key], value)
+		}
+	}
+
+	merged := NewHeader().Merge(header)
+	if merged.Get("Authorization") != "Bearer token" {
+		t.Errorf("Merge() of Headers() returned %s, expected Bearer token", merged.Get("Authorization"))
+	}
+}
+
+func TestHeader_NonStringValues(t *testing.T) {
+	h := NewHeader()
+	h.Properties().Add("X-Retry-Count", 3)
+	h.Properties().Add("X-Debug", true)
+
+	if value := h.Get("X-Retry-Count"); value != "3" {
+		t.Errorf("Header Get() returned %s, expected 3", value)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("X-Retry-Count") + " " + r.Header.Get("X-Debug")))
+	}))
+	defer server.Close()
+
+	response, err := NewRequest(server.URL, WithHeader(h)).Send()
+	if err != nil || string(response.Data) != "3 true" {
+		t.Errorf("Send() returned (%s, %v), expected the values sent as 3 true", response.Data, err)
+	}
+}
+
+func TestHeader_MultipleValues(t *testing.T) {
+	h := Headers().Append("X-Forwarded-For", "10.0.0.1").Append("X-Forwarded-For", "10.0.0.2")
+	h.Add("Accept", "application/json")
+
+	if values := h.Values("X-Forwarded-For"); len(values) != 2 || values[0] != "10.0.0.1" || values[1] != "10.0.0.2" {
+		t.Errorf("Header Values() returned %v, expected [10.0.0.1 10.0.0.2]", values)
+	}
+	if value := h.Get("X-Forwarded-For"); value != "10.0.0.1" {
+		t.Errorf("Header Get() returned %s, expected the first value 10.0.0.1", value)
+	}
+
+	if h.Add("Accept", "text/xml"); h.Get("Accept") != "text/xml" || len(h.Values("Accept")) != 1 {
+		t.Errorf("Header Add() left %v, expected the value replaced", h.Values("Accept"))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Set-Cookie", "a=1")
+		w.Header().Add("Set-Cookie", "b=2")
+		_, _ = w.Write([]byte(strings.Join(r.Header.Values("X-Forwarded-For"), " ")))
+	}))
+	defer server.Close()
+
+	response, err := NewRequest(server.URL, WithHeader(h)).Send()
+	if err != nil || string(response.Data) != "10.0.0.1 10.0.0.2" {
+		t.Errorf("Send() returned (%s, %v), expected both values sent as separate lines", response.Data, err)
+	}
+	if values := asHeader(response.Header).Values("Set-Cookie"); len(values) != 2 {
+		t.Errorf("Response Header Values() returned %v, expected both Set-Cookie values", values)
+	}
+	if values := asHeader(response.Request.Header).Values("X-Forwarded-For"); len(values) != 2 {
+		t.Errorf("Request Header Values() returned %v, expected both X-Forwarded-For values", values)
+	}
+}
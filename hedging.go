@@ -0,0 +1,150 @@
+package room
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WithHedging sends up to maxExtra duplicates of a slow request, see HedgingMiddleware.
+func WithHedging(after time.Duration, maxExtra int) OptionRequest {
+	return WithMiddleware(HedgingMiddleware(after, maxExtra))
+}
+
+// HedgingMiddleware sends a duplicate of a request that did not get a response within after, and another one every
+// after until maxExtra were sent. The first response wins and the other attempts are cancelled. An attempt failing
+// does not trigger a duplicate, retries are left to WithRetry, the error being returned once every attempt failed.
+// Only safe and idempotent methods are hedged, along with requests carrying an Idempotency-Key, see WithIdempotencyKey.
+// A body is sent again through GetBody, a request without one is sent once. A ReaderBody, ReaderBodyAutoDetect or a
+// multipart body with file readers is never hedged: every attempt would read the same reader concurrently.
+func HedgingMiddleware(after time.Duration, maxExtra int) Middleware {
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if maxExtra <= 0 || !isHedgeable(req) {
+				return next(req)
+			}
+
+			return hedge(req, next, after, maxExtra)
+		}
+	}
+}
+
+func isHedgeable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	if req.Context().Value(sharedBodyKey{}) != nil {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return req.Header.Get(headerKeyIdempotencyKey) != ""
+}
+
+type hedgeResult struct {
+	response *http.Response
+	err      error
+	index    int
+}
+
+func hedge(req *http.Request, next RoundTripperFunc, after time.Duration, maxExtra int) (*http.Response, error) {
+	results := make(chan hedgeResult, maxExtra+1)
+	var cancels []context.CancelFunc
+
+	launch := func(body io.ReadCloser) {
+		ctx, cancel := context.WithCancel(req.Context())
+		attempt := req.Clone(ctx)
+		attempt.Body = body
+		index := len(cancels)
+		cancels = append(cancels, cancel)
+
+		go func() {
+			response, err := next(attempt)
+			results <- hedgeResult{response: response, err: err, index: index}
+		}()
+	}
+
+	launch(req.Body)
+
+	timer := time.NewTimer(after)
+	defer timer.Stop()
+
+	pending := 1
+	var lastErr error
+
+	for pending > 0 {
+		select {
+		case <-timer.C:
+			if len(cancels) > maxExtra {
+				continue
+			}
+
+			// A body that cannot be read again leaves the attempts already sent on their own.
+			if body, err := hedgeBody(req); err == nil {
+				launch(body)
+				pending++
+				timer.Reset(after)
+			}
+		case result := <-results:
+			pending--
+
+			if result.err != nil {
+				cancels[result.index]()
+				lastErr = result.err
+
+				continue
+			}
+
+			for i, cancel := range cancels {
+				if i != result.index {
+					cancel()
+				}
+			}
+
+			go discardHedges(results, pending)
+
+			result.response.Body = &cancelingBody{ReadCloser: result.response.Body, cancel: cancels[result.index]}
+
+			return result.response, nil
+		}
+	}
+
+	return nil, lastErr
+}
+
+// hedgeBody returns a body for a duplicate of req, which is empty without GetBody, see isHedgeable.
+func hedgeBody(req *http.Request) (io.ReadCloser, error) {
+	if req.GetBody == nil {
+		return req.Body, nil
+	}
+
+	return req.GetBody()
+}
+
+// discardHedges closes the responses of the cancelled attempts that still made it.
+func discardHedges(results <-chan hedgeResult, pending int) {
+	for ; pending > 0; pending-- {
+		if result := <-results; result.response != nil {
+			_ = result.response.Body.Close()
+		}
+	}
+}
+
+// cancelingBody releases the context of the winning attempt once its body is closed.
+type cancelingBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+
+	return err
+}
@@ -0,0 +1,91 @@
+package room
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRequest_SendWithHedging(t *testing.T) {
+	var calls, abandoned atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			select {
+			case <-r.Context().Done():
+				abandoned.Add(1)
+			case <-time.After(2 * time.Second):
+			}
+
+			return
+		}
+
+		_, _ = w.Write([]byte("hedged"))
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	response, err := NewRequest(server.URL, WithHedging(50*time.Millisecond, 2)).Send()
+	if err != nil || string(response.Data) != "hedged" || time.Since(start) > time.Second {
+		t.Fatalf("Send() returned (%s, %v) after %s, expected the response of the duplicate", response.Data, err, time.Since(start))
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for abandoned.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if calls.Load() != 2 || abandoned.Load() != 1 {
+		t.Errorf("Hedging sent %d attempts and cancelled %d, expected the slow attempt cancelled once the duplicate won", calls.Load(), abandoned.Load())
+	}
+}
+
+func TestRequest_SendWithHedgingUnsafeMethod(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		time.Sleep(50 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	_, _ = NewRequest(server.URL, WithMethod(POST), WithBody(JSONBody(map[string]string{"a": "b"})), WithHedging(5*time.Millisecond, 2)).Send()
+	if calls.Load() != 1 {
+		t.Errorf("Hedging sent a POST %d times, expected it sent once", calls.Load())
+	}
+
+	calls.Store(0)
+	_, _ = NewRequest(server.URL, WithMethod(POST), WithBody(JSONBody(map[string]string{"a": "b"})), WithIdempotencyKey(""), WithHedging(5*time.Millisecond, 2)).Send()
+	if calls.Load() != 3 {
+		t.Errorf("Hedging sent a POST with an Idempotency-Key %d times, expected 3 attempts", calls.Load())
+	}
+}
+
+func TestRequest_SendWithHedgingReaderBody(t *testing.T) {
+	var mu sync.Mutex
+	var received []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		received = append(received, len(data))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	pr, pw := io.Pipe()
+	go func() {
+		for i := 0; i < 10; i++ {
+			time.Sleep(5 * time.Millisecond)
+			_, _ = pw.Write(bytes.Repeat([]byte("a"), 100))
+		}
+		_ = pw.Close()
+	}()
+
+	_, err := NewRequest(server.URL, WithMethod(PUT), WithBody(ReaderBody(pr, "text/plain")), WithHedging(5*time.Millisecond, 2)).Send()
+	if err != nil || len(received) != 1 || received[0] != 1000 {
+		t.Errorf("Hedging a ReaderBody sent bodies of %v bytes (%v), expected the 1000 bytes sent once", received, err)
+	}
+}
@@ -0,0 +1,28 @@
+package room
+
+import "sync"
+
+// hostMap lazily creates one value per host, for state shared by the requests to the same host.
+type hostMap[T any] struct {
+	mu     sync.Mutex
+	values map[string]T
+	create func() T
+}
+
+func newHostMap[T any](create func() T) *hostMap[T] {
+	return &hostMap[T]{values: map[string]T{}, create: create}
+}
+
+func (m *hostMap[T]) get(host string) T {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	value, ok := m.values[host]
+
+	if !ok {
+		value = m.create()
+		m.values[host] = value
+	}
+
+	return value
+}
@@ -0,0 +1,108 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// WithHostHeaderClient sends header with the requests of the client to the hosts matching pattern, which is a host
+// such as "api.example.com", "api.example.com:8443" to match a port too, or "*.example.com" for the subdomains.
+// Headers of the request and of WithHeaderClient win on key collision, as does the first of several patterns matching
+// the same host. The headers are removed when a redirect leaves the matching hosts, so they never reach another one.
+func WithHostHeaderClient(pattern string, header IHeader) OptionClient {
+	return func(client *Client) {
+		client.hostHeaders = append(client.hostHeaders, hostHeader{pattern: strings.ToLower(pattern), header: header})
+	}
+}
+
+type hostHeader struct {
+	pattern string
+	header  IHeader
+}
+
+type hostHeaders []hostHeader
+
+// hostHeadersKey holds the keys set by hostHeaders.apply in the request context, for the redirect policy.
+type hostHeadersKey struct{}
+
+func (h hostHeader) matches(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+
+	if strings.Contains(h.pattern, ":") {
+		host = strings.ToLower(u.Host)
+	}
+
+	if suffix, ok := strings.CutPrefix(h.pattern, "*."); ok {
+		return strings.HasSuffix(host, "."+suffix)
+	}
+
+	return host == h.pattern
+}
+
+// apply sets the headers of the rules matching u missing from header, returning the keys it set.
+func (h hostHeaders) apply(u *url.URL, header http.Header) []string {
+	var injected []string
+
+	for _, rule := range h {
+		if rule.header == nil || !rule.matches(u) {
+			continue
+		}
+
+		var added []string
+
+		asHeader(rule.header).Each(func(key, value string) {
+			key = http.CanonicalHeaderKey(key)
+
+			if _, ok := header[key]; ok && !slices.Contains(added, key) {
+				return
+			}
+
+			header.Add(key, value)
+
+			if !slices.Contains(added, key) {
+				added = append(added, key)
+			}
+		})
+
+		injected = append(injected, added...)
+	}
+
+	return injected
+}
+
+// inject applies the rules to req, recording the keys it set in the context of the returned request.
+func (h hostHeaders) inject(req *http.Request) *http.Request {
+	if injected := h.apply(req.URL, req.Header); len(injected) > 0 {
+		return req.WithContext(context.WithValue(req.Context(), hostHeadersKey{}, injected))
+	}
+
+	return req
+}
+
+// checkRedirect replaces the headers set for the first host by those of the host redirected to, if any,
+// before running policy. Without policy it stops after 10 redirects as the default of http.Client does.
+func (h hostHeaders) checkRedirect(policy func(req *http.Request, via []*http.Request) error) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if injected, ok := req.Context().Value(hostHeadersKey{}).([]string); ok {
+			for _, key := range injected {
+				req.Header.Del(key)
+			}
+		}
+
+		h.apply(req.URL, req.Header)
+
+		if policy != nil {
+			return policy(req, via)
+		}
+
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+
+		return nil
+	}
+}
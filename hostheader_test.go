@@ -0,0 +1,76 @@
+package room
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestClient_WithHostHeaderClient(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("key=" + r.Header.Get("X-Api-Key")))
+	}))
+	defer other.Close()
+
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, other.URL, http.StatusFound)
+			return
+		}
+
+		_, _ = w.Write([]byte("key=" + strings.Join(r.Header.Values("X-Api-Key"), ",")))
+	}))
+	defer api.Close()
+
+	apiHost := strings.TrimPrefix(api.URL, "http://")
+	client := NewClient("",
+		WithHostHeaderClient(apiHost, NewHeader().Add("X-Api-Key", "secret")),
+		WithHostHeaderClient("*.example.com", NewHeader().Add("X-Api-Key", "example")))
+
+	tests := []struct {
+		request  *Request
+		expected string
+	}{
+		{client.Get(api.URL), "key=secret"},
+		{client.Get(other.URL), "key="},
+		{client.Get(api.URL, WithHeader(NewHeader().Add("x-api-key", "override"))), "key=override"},
+		{client.Get(api.URL + "/redirect"), "key="},
+	}
+
+	for _, test := range tests {
+		response, err := test.request.Send()
+		if err != nil || string(response.Data) != test.expected {
+			t.Errorf("Send() to %s returned (%s, %v), expected %s", test.request.path, response.Data, err, test.expected)
+		}
+	}
+
+	if response, _ := client.Get(api.URL+"/redirect", WithMaxRedirects(0)).Send(); response.StatusCode != http.StatusFound {
+		t.Errorf("Send() WithMaxRedirects(0) returned %d, expected the redirect policy of the request kept", response.StatusCode)
+	}
+}
+
+func TestHostHeader_Matches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		url     string
+		matches bool
+	}{
+		{"api.example.com", "https://API.example.com/users", true},
+		{"api.example.com", "https://api.example.com:8443", true},
+		{"api.example.com:8443", "https://api.example.com", false},
+		{"api.example.com:8443", "https://api.example.com:8443", true},
+		{"*.example.com", "https://api.example.com", true},
+		{"*.example.com", "https://example.com", false},
+		{"*.example.com", "https://api.example.com.evil.io", false},
+		{"api.example.com", "https://api.example.com.evil.io", false},
+	}
+
+	for _, test := range tests {
+		u, _ := url.Parse(test.url)
+		if matches := (hostHeader{pattern: test.pattern}).matches(u); matches != test.matches {
+			t.Errorf("hostHeader(%s).matches(%s) returned %t, expected %t", test.pattern, test.url, matches, test.matches)
+		}
+	}
+}
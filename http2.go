@@ -0,0 +1,41 @@
+package room
+
+import (
+	"crypto/tls"
+	"net/http"
+	"slices"
+)
+
+const protoH2 = "h2"
+
+// WithHTTP2 forces HTTP/2 over TLS on, even with a custom dialer or TLS config which otherwise opt the transport out of it,
+// or forces HTTP/1.1 off by dropping h2 from the ALPN protocols. Cleartext URLs keep speaking HTTP/1.1, see the h2c package.
+func WithHTTP2(enabled bool) OptionRequest {
+	return func(request *Request) {
+		request.addTransportOption(http2Option{enabled: enabled}, func(transport *http.Transport) {
+			if enabled {
+				transport.ForceAttemptHTTP2 = true
+
+				// A nil map lets the transport register its bundled HTTP/2 implementation on first use.
+				if transport.TLSNextProto != nil && transport.TLSNextProto[protoH2] == nil {
+					transport.TLSNextProto = nil
+				}
+
+				return
+			}
+
+			transport.ForceAttemptHTTP2 = false
+			transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+
+			if transport.TLSClientConfig != nil {
+				transport.TLSClientConfig.NextProtos = slices.DeleteFunc(slices.Clone(transport.TLSClientConfig.NextProtos), func(proto string) bool {
+					return proto == protoH2
+				})
+			}
+		})
+	}
+}
+
+type http2Option struct {
+	enabled bool
+}
@@ -0,0 +1,47 @@
+package room
+
+import (
+	"crypto/tls"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequest_SendWithHTTP2(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Proto))
+	}))
+	server.EnableHTTP2 = true
+	server.TLS = &tls.Config{NextProtos: []string{"h2", "http/1.1"}}
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	// A transport with a TLS config of its own only attempts HTTP/2 when forced to.
+	custom := func() OptionRequest {
+		return WithTransport(&http.Transport{TLSClientConfig: &tls.Config{NextProtos: []string{"http/1.1"}}})
+	}
+
+	tests := []struct {
+		opts     []OptionRequest
+		expected string
+	}{
+		{[]OptionRequest{WithInsecureSkipVerify()}, "HTTP/2.0"},
+		{[]OptionRequest{WithInsecureSkipVerify(), WithHTTP2(false)}, "HTTP/1.1"},
+		{[]OptionRequest{custom(), WithInsecureSkipVerify()}, "HTTP/1.1"},
+		{[]OptionRequest{custom(), WithInsecureSkipVerify(), WithHTTP2(true)}, "HTTP/2.0"},
+		{[]OptionRequest{WithHTTP2(false), WithHTTP2(true), WithInsecureSkipVerify()}, "HTTP/2.0"},
+	}
+
+	for _, test := range tests {
+		response, err := NewRequest(server.URL, test.opts...).Send()
+		if err != nil {
+			t.Fatalf("Send() returned unexpected error: %v", err)
+		}
+		if proto := string(response.Data); proto != test.expected || response.raw.Proto != test.expected {
+			t.Errorf("Send() was served over %s, expected %s", proto, test.expected)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package room
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// HTTPError is returned for responses whose status is not 2xx, see Response.Error and WithErrorOnHTTPError.
+// Body holds the buffered response body.
+type HTTPError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	Header     http.Header
+	Body       []byte
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%s %s: unexpected status %d %s", e.Method, e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// WithErrorOnHTTPError makes Send return the *HTTPError of Response.Error along with the response
+// when the status is not 2xx, so HTTP failures take the same err path as network ones.
+func WithErrorOnHTTPError() OptionRequest {
+	return func(request *Request) {
+		request.errorOnStatus = true
+	}
+}
+
+// Error returns an *HTTPError when the status is not 2xx and nil otherwise.
+// A body left unread by WithStream is buffered into the error.
+func (r Response) Error() error {
+	if r.OK() {
+		return nil
+	}
+
+	body, _ := r.Bytes()
+
+	err := &HTTPError{StatusCode: r.StatusCode, Method: r.Request.Method, URL: r.Request.URI.String(), Body: body}
+
+	if r.raw != nil {
+		err.Header = r.raw.Header.Clone()
+	}
+
+	if r.StatusCode == http.StatusPreconditionFailed {
+		return &PreconditionFailedError{HTTPError: err, ETag: err.Header.Get(headerKeyETag)}
+	}
+
+	return err
+}
+
+// PreconditionFailedError is the error of a 412 Precondition Failed response: the resource changed since the ETag
+// sent WithIfMatch was read, refetch it and retry the update. It unwraps to its *HTTPError.
+type PreconditionFailedError struct {
+	*HTTPError
+	// ETag is the current ETag of the resource when the server sent it.
+	ETag string
+}
+
+func (e *PreconditionFailedError) Unwrap() error {
+	return e.HTTPError
+}
+
+// IsPreconditionFailed reports whether err is, or wraps, a *PreconditionFailedError.
+func IsPreconditionFailed(err error) bool {
+	var preconditionErr *PreconditionFailedError
+
+	return errors.As(err, &preconditionErr)
+}
+
+const headerKeyIfMatch = "If-Match"
+
+// WithIfMatch applies the update only if the resource still has the given ETag, the server answering
+// 412 Precondition Failed otherwise, see PreconditionFailedError. An unquoted etag is quoted, "*" is sent as is.
+func WithIfMatch(etag string) OptionRequest {
+	return func(request *Request) {
+		request.ifMatch = quoteETag(etag)
+	}
+}
+
+// WithIfNoneMatch applies the request only if the resource does not have the given ETag, "*" creating it only
+// if it does not exist yet. A GET with a matching ETag is answered 304 Not Modified.
+func WithIfNoneMatch(etag string) OptionRequest {
+	return func(request *Request) {
+		request.ifNoneMatch = quoteETag(etag)
+	}
+}
+
+// quoteETag quotes an entity tag given without its quotes, keeping a weak W/ prefix.
+func quoteETag(etag string) string {
+	if etag == "*" || strings.HasSuffix(etag, `"`) {
+		return etag
+	}
+
+	if weak, ok := strings.CutPrefix(etag, "W/"); ok {
+		return `W/"` + weak + `"`
+	}
+
+	return `"` + etag + `"`
+}
@@ -0,0 +1,84 @@
+package room
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequest_SendWithErrorOnHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.Header().Set("X-Request-Id", "42")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	if _, err := NewRequest(server.URL + "/missing").Send(); err != nil {
+		t.Errorf("Send() returned %v without WithErrorOnHTTPError, expected nil", err)
+	}
+
+	for _, stream := range []bool{false, true} {
+		opts := []OptionRequest{WithErrorOnHTTPError()}
+		if stream {
+			opts = append(opts, WithStream())
+		}
+
+		response, err := NewRequest(server.URL+"/missing", opts...).Send()
+
+		var httpErr *HTTPError
+		if !errors.As(err, &httpErr) || response.StatusCode != http.StatusNotFound {
+			t.Fatalf("Send() returned (%d, %v), expected an *HTTPError", response.StatusCode, err)
+		}
+		if httpErr.StatusCode != http.StatusNotFound || string(httpErr.Body) != `{"error":"not found"}` || httpErr.Header.Get("X-Request-Id") != "42" {
+			t.Errorf("Send() returned %+v, expected the status, headers and buffered body", httpErr)
+		}
+		if httpErr.Error() != "GET "+server.URL+"/missing: unexpected status 404 Not Found" {
+			t.Errorf("HTTPError.Error() returned %s", httpErr.Error())
+		}
+	}
+
+	if _, err := NewRequest(server.URL, WithErrorOnHTTPError()).Send(); err != nil {
+		t.Errorf("Send() returned %v for a 200, expected nil", err)
+	}
+}
+
+func TestRequest_SendWithIfMatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v2"`)
+
+		if r.Header.Get("If-Match") != `"v2"` || r.Header.Get("If-None-Match") != `W/"v1"` {
+			w.WriteHeader(http.StatusPreconditionFailed)
+		}
+	}))
+	defer server.Close()
+
+	_, err := NewRequest(server.URL, WithMethod(PUT), WithIfMatch("v1"), WithIfNoneMatch(`W/v1`), WithErrorOnHTTPError()).Send()
+
+	var preconditionErr *PreconditionFailedError
+	var httpErr *HTTPError
+	if !errors.As(err, &preconditionErr) || preconditionErr.ETag != `"v2"` || !errors.As(err, &httpErr) || !IsPreconditionFailed(err) {
+		t.Fatalf("Send() returned %v, expected a *PreconditionFailedError carrying the current ETag", err)
+	}
+
+	if _, err = NewRequest(server.URL, WithMethod(PUT), WithIfMatch(preconditionErr.ETag), WithIfNoneMatch("W/v1"), WithErrorOnHTTPError()).Send(); err != nil {
+		t.Errorf("Send() with the current ETag returned %v, expected nil", err)
+	}
+
+	header := WithHeader(NewHeader().Add("X-Trace", "1"))
+	for _, opts := range [][]OptionRequest{
+		{WithIfMatch("v2"), WithIfNoneMatch("W/v1"), header},
+		{header, WithIfMatch("v2"), WithIfNoneMatch("W/v1")},
+	} {
+		if _, err = NewRequest(server.URL, append(opts, WithMethod(PUT), WithErrorOnHTTPError())...).Send(); err != nil {
+			t.Errorf("Send() with the preconditions and WithHeader returned %v, expected nil", err)
+		}
+	}
+
+	if etag := quoteETag("*"); etag != "*" {
+		t.Errorf("quoteETag(*) returned %s, expected *", etag)
+	}
+}
@@ -0,0 +1,80 @@
+package room
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// FromHTTPRequest wraps a request built elsewhere to send it with the retries, middlewares and Response of room.
+// Its method, URL, Host, headers and body are copied, opts applying on top of them. Its context is used as with WithContext,
+// unless it is the background one. The body is sent again on a retry through req.GetBody, or replayed as a ReaderBody.
+func FromHTTPRequest(req *http.Request, opts ...OptionRequest) *Request {
+	header := Headers()
+
+	for key, values := range req.Header {
+		for _, value := range values {
+			header.Append(key, value)
+		}
+	}
+
+	base := []OptionRequest{WithMethod(HTTPMethod(req.Method)), WithHeader(header)}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		base = append(base, WithBody(&httpRequestBody{req: req, replay: ReaderBody(req.Body, "")}))
+	}
+
+	if req.Host != "" && req.Host != req.URL.Host {
+		base = append(base, WithHost(req.Host))
+	}
+
+	if ctx := req.Context(); ctx != context.Background() {
+		base = append(base, WithContext(ctx))
+	}
+
+	return NewRequest(req.URL.String(), append(base, opts...)...)
+}
+
+// httpRequestBody sends the body of a wrapped request, keeping its Content-Length. Its Content-Type is in the headers.
+type httpRequestBody struct {
+	mu     sync.Mutex
+	req    *http.Request
+	replay IBodyParser
+	parsed bool
+}
+
+func (b *httpRequestBody) Parse() (io.Reader, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var body io.Reader = b.req.Body
+
+	if b.req.GetBody == nil {
+		reader, err := b.replay.Parse()
+
+		if err != nil {
+			return nil, err
+		}
+
+		body = reader
+	} else if b.parsed {
+		reader, err := b.req.GetBody()
+
+		if err != nil {
+			return nil, err
+		}
+
+		body = reader
+	}
+
+	b.parsed = true
+
+	if b.req.ContentLength > 0 && bodyLength(body) < 0 {
+		return &sizedReader{closingReader: closingReader{reader: body, source: body}, remaining: int(b.req.ContentLength)}, nil
+	}
+
+	return body, nil
+}
+
+func (b *httpRequestBody) ContentType() string { return "" }
@@ -0,0 +1,65 @@
+package room
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFromHTTPRequest(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("X-Received", r.Method+" "+r.URL.RequestURI()+" "+r.Header.Get("X-Trace")+" "+r.Header.Get(headerKeyContentType))
+		w.Header().Set("X-Length", r.Header.Get("Content-Length"))
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	for name, body := range map[string]io.Reader{"GetBody": strings.NewReader("payload"), "reader": io.NopCloser(strings.NewReader("payload"))} {
+		attempts.Store(0)
+
+		req, _ := http.NewRequest(http.MethodPut, server.URL+"/items?id=1", body)
+		req.Header.Set("X-Trace", "abc")
+		req.Header.Set(headerKeyContentType, "text/plain")
+		if name == "reader" {
+			req.ContentLength = 7
+		}
+
+		response, err := FromHTTPRequest(req, WithRetry(2, ConstantBackoff(time.Millisecond))).Send()
+		if err != nil || response.StatusCode != http.StatusOK || string(response.Data) != "payload" {
+			t.Fatalf("Send() with a %s body returned (%d %s, %v), expected the body sent again on the retry", name, response.StatusCode, response.Data, err)
+		}
+
+		if received := response.Header.Get("X-Received"); received != "PUT /items?id=1 abc text/plain" || response.Header.Get("X-Length") != "7" {
+			t.Errorf("Send() with a %s body sent %s with Content-Length %s, expected the method, URL, headers and length of the wrapped request", name, received, response.Header.Get("X-Length"))
+		}
+	}
+}
+
+func TestFromHTTPRequestContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://127.0.0.1:1", nil)
+	if request := FromHTTPRequest(req); request.ctx != ctx || request.Method != GET {
+		t.Errorf("FromHTTPRequest() returned context %v and method %s, expected those of the request", request.ctx, request.Method)
+	}
+
+	req, _ = http.NewRequest(http.MethodPost, "http://127.0.0.1:1", bytes.NewReader(nil))
+	if request := FromHTTPRequest(req, WithMethod(PATCH)); request.ctx != nil || request.Method != PATCH {
+		t.Errorf("FromHTTPRequest() returned context %v and method %s, expected no context and the method of the option", request.ctx, request.Method)
+	}
+}
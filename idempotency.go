@@ -0,0 +1,46 @@
+package room
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+)
+
+const headerKeyIdempotencyKey = "Idempotency-Key"
+
+// WithIdempotencyKey sends key in the Idempotency-Key header so the server can deduplicate a non-idempotent request,
+// a payment for instance. An empty key is replaced by a random UUID generated once, when the option is applied,
+// so every attempt of WithRetry and every Send of the request carry the same key. Build a new request per operation.
+// The header also lets the transport replay the request when a kept-alive connection turns out to be closed.
+// The key wins over an Idempotency-Key passed with WithHeader. Send fails when no random key can be generated.
+func WithIdempotencyKey(key string) OptionRequest {
+	return func(request *Request) {
+		value := key
+
+		if value == "" {
+			var err error
+
+			if value, err = newUUID(); err != nil {
+				request.idempotencyErr = err
+
+				return
+			}
+		}
+
+		request.idempotencyKey = value
+	}
+}
+
+// newUUID returns a random version 4 UUID.
+func newUUID() (string, error) {
+	var b [16]byte
+
+	if _, err := io.ReadFull(rand.Reader, b[:]); err != nil {
+		return "", fmt.Errorf("generate idempotency key: %w", err)
+	}
+
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
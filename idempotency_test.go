@@ -0,0 +1,71 @@
+package room
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"testing/iotest"
+)
+
+func TestRequest_SendWithIdempotencyKey(t *testing.T) {
+	var keys []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(headerKeyIdempotencyKey))
+
+		if len(keys) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	if _, err := NewRequest(server.URL, WithMethod(POST), WithIdempotencyKey(""), WithRetry(3, nil)).Send(); err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	if len(keys) != 3 || !uuid.MatchString(keys[0]) || keys[1] != keys[0] || keys[2] != keys[0] {
+		t.Errorf("Send() sent the keys %v, expected the same generated UUID on every attempt", keys)
+	}
+
+	keys = nil
+
+	_, _ = NewRequest(server.URL, WithMethod(POST), WithIdempotencyKey("order-42")).Send()
+	if len(keys) != 1 || keys[0] != "order-42" {
+		t.Errorf("Send() sent the keys %v, expected order-42", keys)
+	}
+
+	for _, opts := range [][]OptionRequest{
+		{WithIdempotencyKey("order-42"), WithHeader(NewHeader().Add("X-Trace", "1"))},
+		{WithHeader(NewHeader().Add("X-Trace", "1")), WithIdempotencyKey("order-42")},
+	} {
+		keys = nil
+
+		_, _ = NewRequest(server.URL, append(opts, WithMethod(POST))...).Send()
+		if len(keys) != 1 || keys[0] != "order-42" {
+			t.Errorf("Send() sent the keys %v along with WithHeader, expected order-42", keys)
+		}
+	}
+
+	generate := WithIdempotencyKey("")
+	first := NewRequest(server.URL, generate).idempotencyKey
+	if second := NewRequest(server.URL, generate).idempotencyKey; first == second {
+		t.Error("WithIdempotencyKey() did not generate a key per request")
+	}
+}
+
+func TestWithIdempotencyKey_RandomFailure(t *testing.T) {
+	defer func(reader io.Reader) { rand.Reader = reader }(rand.Reader)
+
+	errEntropy := errors.New("no entropy")
+	rand.Reader = iotest.ErrReader(errEntropy)
+
+	if _, err := NewRequest("http://127.0.0.1:1", WithIdempotencyKey("")).Send(); !errors.Is(err, errEntropy) {
+		t.Errorf("Send() returned %v, expected the error of the random source", err)
+	}
+}
@@ -0,0 +1,110 @@
+package room
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrJSONPathNotFound = errors.New("json path not found")
+
+// JSONPath extracts a value of the JSON body by a dotted path, e.g. "data.items.0.id". Array elements are selected by
+// index, as a segment or in brackets, "data.items[0].id" and "$.data.items[0].id" being the same path.
+// Values are decoded as by encoding/json into an any, numbers as float64. A missing key or index wraps ErrJSONPathNotFound.
+func (r Response) JSONPath(expr string) (any, error) {
+	segments, err := parseJSONPath(expr)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := r.Bytes()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var value any
+
+	if err = json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("decode json body: %w", err)
+	}
+
+	for i, segment := range segments {
+		switch node := value.(type) {
+		case map[string]any:
+			child, ok := node[segment]
+
+			if !ok {
+				return nil, jsonPathError(segments[:i+1])
+			}
+
+			value = child
+		case []any:
+			index, err := strconv.Atoi(segment)
+
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, jsonPathError(segments[:i+1])
+			}
+
+			value = node[index]
+		default:
+			return nil, jsonPathError(segments[:i+1])
+		}
+	}
+
+	return value, nil
+}
+
+func jsonPathError(segments []string) error {
+	return fmt.Errorf("%w: %s", ErrJSONPathNotFound, strings.Join(segments, "."))
+}
+
+// parseJSONPath splits expr into keys and indexes, a key containing a dot is escaped as "a\.b".
+func parseJSONPath(expr string) ([]string, error) {
+	expr = strings.TrimPrefix(strings.TrimPrefix(expr, "$"), ".")
+
+	var segments []string
+	var current strings.Builder
+
+	for i := 0; i < len(expr); i++ {
+		switch c := expr[i]; c {
+		case '\\':
+			if i+1 < len(expr) {
+				i++
+				current.WriteByte(expr[i])
+			}
+		case '.':
+			segments = append(segments, current.String())
+			current.Reset()
+		case '[':
+			end := strings.IndexByte(expr[i:], ']')
+
+			if end < 0 {
+				return nil, fmt.Errorf("invalid json path %q: unclosed bracket", expr)
+			}
+
+			if current.Len() > 0 {
+				segments = append(segments, current.String())
+				current.Reset()
+			}
+
+			segments = append(segments, strings.Trim(expr[i+1:i+end], `'"`))
+			i += end
+
+			if i+1 < len(expr) && expr[i+1] == '.' {
+				i++
+			}
+		default:
+			current.WriteByte(c)
+		}
+	}
+
+	if current.Len() > 0 || len(segments) == 0 && expr != "" {
+		segments = append(segments, current.String())
+	}
+
+	return segments, nil
+}
@@ -0,0 +1,39 @@
+package room
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResponse_JSONPath(t *testing.T) {
+	response := newTestResponse(headerValueApplicationJson, `{"data":{"items":[{"id":7,"tags":["a","b"]}],"a.b":true,"total":1}}`)
+
+	tests := map[string]any{
+		"data.items.0.id":     float64(7),
+		"data.items[0].id":    float64(7),
+		"$.data.items[0].id":  float64(7),
+		"data.items.0.tags.1": "b",
+		`data.a\.b`:           true,
+		`data["total"]`:       float64(1),
+	}
+
+	for expr, expected := range tests {
+		if value, err := response.JSONPath(expr); err != nil || value != expected {
+			t.Errorf("JSONPath(%s) returned (%v, %v), expected %v", expr, value, err, expected)
+		}
+	}
+
+	if value, err := response.JSONPath(""); err != nil || value == nil {
+		t.Errorf("JSONPath() of an empty path returned (%v, %v), expected the whole document", value, err)
+	}
+
+	for _, expr := range []string{"data.missing", "data.items.1", "data.items.x", "data.total.value"} {
+		if _, err := response.JSONPath(expr); !errors.Is(err, ErrJSONPathNotFound) {
+			t.Errorf("JSONPath(%s) returned %v, expected ErrJSONPathNotFound", expr, err)
+		}
+	}
+
+	if _, err := response.JSONPath("data.items[0"); err == nil || errors.Is(err, ErrJSONPathNotFound) {
+		t.Errorf("JSONPath() of an invalid path returned %v, expected a syntax error", err)
+	}
+}
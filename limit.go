@@ -0,0 +1,66 @@
+package room
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+var ErrResponseTooLarge = errors.New("response body exceeds the maximum size")
+
+// WithMaxResponseSize fails reading a response body larger than n bytes with ErrResponseTooLarge, once decompressed,
+// rather than buffering it whole. A Content-Length above n fails before anything is read. Streamed bodies fail mid-read.
+func WithMaxResponseSize(n int64) OptionRequest {
+	return func(request *Request) {
+		request.maxResponseSize = n
+	}
+}
+
+func limitBody(response *http.Response, n int64) {
+	if response.Body == nil || response.Body == http.NoBody {
+		return
+	}
+
+	response.Body = &limitedBody{ReadCloser: response.Body, limit: n, remaining: n, declared: response.ContentLength}
+}
+
+// limitedBody reads up to limit bytes and errors when the body does not end there, as io.LimitReader would silently truncate.
+type limitedBody struct {
+	io.ReadCloser
+	limit     int64
+	remaining int64
+	declared  int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.declared > b.limit {
+		return 0, b.tooLarge()
+	}
+
+	if b.remaining <= 0 {
+		// Reading one more byte tells a body of exactly limit bytes from a larger one.
+		var probe [1]byte
+
+		n, err := b.ReadCloser.Read(probe[:])
+
+		if n > 0 {
+			return 0, b.tooLarge()
+		}
+
+		return 0, err
+	}
+
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+
+	return n, err
+}
+
+func (b *limitedBody) tooLarge() error {
+	return fmt.Errorf("%w of %d bytes", ErrResponseTooLarge, b.limit)
+}
@@ -0,0 +1,52 @@
+package room
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequest_SendWithMaxResponseSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("chunked") {
+			w.(http.Flusher).Flush()
+		}
+
+		_, _ = w.Write([]byte(strings.Repeat("a", 10)))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		path     string
+		limit    int64
+		tooLarge bool
+	}{
+		{"/", 10, false},
+		{"/", 9, true},
+		{"/?chunked", 10, false},
+		{"/?chunked", 9, true},
+	}
+
+	for _, test := range tests {
+		response, err := NewRequest(server.URL+test.path, WithMaxResponseSize(test.limit)).Send()
+
+		if test.tooLarge && !errors.Is(err, ErrResponseTooLarge) {
+			t.Errorf("Send() %s with a limit of %d returned %v, expected ErrResponseTooLarge", test.path, test.limit, err)
+		}
+		if !test.tooLarge && (err != nil || len(response.Data) != 10) {
+			t.Errorf("Send() %s with a limit of %d returned (%d bytes, %v), expected the whole body", test.path, test.limit, len(response.Data), err)
+		}
+	}
+
+	response, err := NewRequest(server.URL+"/?chunked", WithMaxResponseSize(4), WithStream()).Send()
+	if err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+
+	if _, err = response.Save(io.Discard); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("Save() returned %v, expected ErrResponseTooLarge", err)
+	}
+}
@@ -0,0 +1,149 @@
+package room
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"time"
+)
+
+// Logger observes every round-trip made by Send, retries included.
+type Logger interface {
+	LogRequest(req *http.Request)
+	LogResponse(response *http.Response, elapsed time.Duration)
+	LogError(req *http.Request, err error, elapsed time.Duration)
+}
+
+func WithLogger(logger Logger) OptionRequest {
+	return WithMiddleware(LoggingMiddleware(logger))
+}
+
+// LoggingMiddleware reports each round-trip passing through it to logger along with its duration.
+func LoggingMiddleware(logger Logger) Middleware {
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			logger.LogRequest(req)
+
+			start := time.Now()
+			response, err := next(req)
+
+			if err != nil {
+				logger.LogError(req, err, time.Since(start))
+			} else {
+				logger.LogResponse(response, time.Since(start))
+			}
+
+			return response, err
+		}
+	}
+}
+
+// StdLogger writes one line per request and response to a *log.Logger.
+// Bodies are only dumped in verbose mode since they may be large or sensitive, sensitive headers are always redacted.
+type StdLogger struct {
+	out     *log.Logger
+	verbose bool
+}
+
+// NewStdLogger logs to out, or to the standard logger when out is nil.
+func NewStdLogger(out *log.Logger, verbose bool) *StdLogger {
+	if out == nil {
+		out = log.Default()
+	}
+
+	return &StdLogger{out: out, verbose: verbose}
+}
+
+func (l *StdLogger) LogRequest(req *http.Request) {
+	if !l.verbose {
+		l.out.Printf("--> %s %s", req.Method, req.URL.Redacted())
+		return
+	}
+
+	dump, err := httputil.DumpRequestOut(redactedRequest(req), true)
+
+	if err != nil {
+		l.out.Printf("--> %s %s (dump failed: %v)", req.Method, req.URL.Redacted(), err)
+		return
+	}
+
+	l.out.Printf("--> %s", dump)
+}
+
+func (l *StdLogger) LogResponse(response *http.Response, elapsed time.Duration) {
+	if !l.verbose {
+		l.out.Printf("<-- %d %s (%s)", response.StatusCode, response.Request.URL.Redacted(), elapsed)
+		return
+	}
+
+	dump, err := httputil.DumpResponse(redactedResponse(response), true)
+
+	if err != nil {
+		l.out.Printf("<-- %d %s (%s, dump failed: %v)", response.StatusCode, response.Request.URL.Redacted(), elapsed, err)
+		return
+	}
+
+	l.out.Printf("<-- (%s) %s", elapsed, dump)
+}
+
+func (l *StdLogger) LogError(req *http.Request, err error, elapsed time.Duration) {
+	l.out.Printf("<-- %s %s failed (%s): %v", req.Method, req.URL.Redacted(), elapsed, err)
+}
+
+// redactedRequest returns a copy of req masked with its redaction config for dumping.
+// The body is buffered and put back so that req can still be sent.
+func redactedRequest(req *http.Request) *http.Request {
+	cfg := redactionFrom(req.Context())
+	redacted := req.Clone(req.Context())
+	redacted.Header = cfg.header(req.Header)
+
+	if req.Body != nil && req.Body != http.NoBody {
+		var body []byte
+		body, req.Body = bufferBody(req.Body)
+		body = cfg.body(body)
+		redacted.Body, redacted.ContentLength = io.NopCloser(bytes.NewReader(body)), int64(len(body))
+	}
+
+	return redacted
+}
+
+func redactedResponse(response *http.Response) *http.Response {
+	if response.Request == nil {
+		return response
+	}
+
+	cfg := redactionFrom(response.Request.Context())
+	redacted := *response
+	redacted.Header = cfg.header(response.Header)
+
+	if response.Body != nil && response.Body != http.NoBody {
+		var body []byte
+		body, response.Body = bufferBody(response.Body)
+		body = cfg.body(body)
+		redacted.Body, redacted.ContentLength = io.NopCloser(bytes.NewReader(body)), int64(len(body))
+	}
+
+	return &redacted
+}
+
+// bufferBody reads body into memory and returns a reader replaying it, a read error being returned by the reader.
+func bufferBody(body io.ReadCloser) ([]byte, io.ReadCloser) {
+	data, err := io.ReadAll(body)
+	_ = body.Close()
+
+	if err != nil {
+		return data, io.NopCloser(io.MultiReader(bytes.NewReader(data), errReader{err}))
+	}
+
+	return data, io.NopCloser(bytes.NewReader(data))
+}
+
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
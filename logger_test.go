@@ -0,0 +1,68 @@
+package room
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequest_SendWithLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("response-secret"))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	_, _ = NewRequest(server.URL,
+		WithMethod(POST),
+		WithBody(JSONBody(map[string]any{"key": "request-secret"})),
+		WithLogger(NewStdLogger(log.New(&out, "", 0), false)),
+	).Send()
+
+	logged := out.String()
+	if !strings.Contains(logged, "--> POST "+server.URL) || !strings.Contains(logged, "<-- 200 "+server.URL) {
+		t.Errorf("StdLogger logged %q, expected request and response lines", logged)
+	}
+	if strings.Contains(logged, "secret") {
+		t.Errorf("StdLogger logged a body without verbose mode: %q", logged)
+	}
+}
+
+func TestRequest_SendWithVerboseLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "response-secret"})
+		_, _ = w.Write([]byte("response-body"))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	response, _ := NewRequest(server.URL,
+		WithMethod(POST),
+		WithBody(JSONBody(map[string]any{"key": "request-body"})),
+		WithHeader(NewHeader().Add("Authorization", "Bearer request-secret")),
+		WithLogger(NewStdLogger(log.New(&out, "", 0), true)),
+	).Send()
+
+	logged := out.String()
+	if !strings.Contains(logged, "request-body") || !strings.Contains(logged, "response-body") {
+		t.Errorf("StdLogger in verbose mode logged %q, expected both bodies", logged)
+	}
+	if strings.Contains(logged, "secret") || !strings.Contains(logged, "Authorization: ***") || !strings.Contains(logged, "Set-Cookie: ***") {
+		t.Errorf("StdLogger in verbose mode logged %q, expected the sensitive headers redacted", logged)
+	}
+	if string(response.Data) != "response-body" {
+		t.Errorf("Send() returned body %q after verbose logging, expected response-body", response.Data)
+	}
+}
+
+func TestRequest_SendWithLoggerError(t *testing.T) {
+	var out bytes.Buffer
+	_, _ = NewRequest("http://127.0.0.1:1", WithLogger(NewStdLogger(log.New(&out, "", 0), false))).Send()
+
+	if !strings.Contains(out.String(), "failed") {
+		t.Errorf("StdLogger logged %q, expected a failure line", out.String())
+	}
+}
@@ -0,0 +1,23 @@
+package room
+
+// Get, Post, Put, Patch and Delete preset the method and body of NewRequest; opts are applied after them.
+
+func Get(path string, opts ...OptionRequest) *Request {
+	return NewRequest(path, append([]OptionRequest{WithMethod(GET)}, opts...)...)
+}
+
+func Post(path string, body IBodyParser, opts ...OptionRequest) *Request {
+	return NewRequest(path, append([]OptionRequest{WithMethod(POST), WithBody(body)}, opts...)...)
+}
+
+func Put(path string, body IBodyParser, opts ...OptionRequest) *Request {
+	return NewRequest(path, append([]OptionRequest{WithMethod(PUT), WithBody(body)}, opts...)...)
+}
+
+func Patch(path string, body IBodyParser, opts ...OptionRequest) *Request {
+	return NewRequest(path, append([]OptionRequest{WithMethod(PATCH), WithBody(body)}, opts...)...)
+}
+
+func Delete(path string, opts ...OptionRequest) *Request {
+	return NewRequest(path, append([]OptionRequest{WithMethod(DELETE)}, opts...)...)
+}
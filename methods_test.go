@@ -0,0 +1,33 @@
+package room
+
+import "testing"
+
+func TestMethodConstructors(t *testing.T) {
+	body := JSONBody(map[string]any{"key": "value"})
+
+	tests := []struct {
+		request *Request
+		method  HTTPMethod
+		body    IBodyParser
+	}{
+		{Get("http://localhost"), GET, dumpBody{}},
+		{Post("http://localhost", body), POST, body},
+		{Put("http://localhost", body), PUT, body},
+		{Patch("http://localhost", body), PATCH, body},
+		{Delete("http://localhost"), DELETE, dumpBody{}},
+	}
+
+	for _, test := range tests {
+		if test.request.Method != test.method {
+			t.Errorf("constructor set method %s, expected %s", test.request.Method, test.method)
+		}
+		if test.request.BodyParser != test.body {
+			t.Errorf("%s constructor set body %v, expected %v", test.method, test.request.BodyParser, test.body)
+		}
+	}
+
+	header := NewHeader().Add("X-Key", "value")
+	if r := Post("http://localhost", body, WithHeader(header)); r.Header != header {
+		t.Error("Post() did not apply the additional options")
+	}
+}
@@ -0,0 +1,59 @@
+package room
+
+import (
+	"net/http"
+	"time"
+)
+
+// MetricsCollector receives golden-signal metrics of every attempt, code is zero when no response was received.
+// Implementations must be safe for concurrent use, the metrics subpackage shows how to feed a Prometheus registry.
+type MetricsCollector interface {
+	ObserveLatency(host, method string, code int, d time.Duration)
+	IncInFlight(host, method string)
+	DecInFlight(host, method string)
+}
+
+// NoopMetrics discards every metric, embed it to implement only some of the hooks.
+type NoopMetrics struct{}
+
+func (NoopMetrics) ObserveLatency(string, string, int, time.Duration) {}
+
+func (NoopMetrics) IncInFlight(string, string) {}
+
+func (NoopMetrics) DecInFlight(string, string) {}
+
+// WithMetrics reports the attempts of the request to collector, see MetricsMiddleware.
+func WithMetrics(collector MetricsCollector) OptionRequest {
+	return WithMiddleware(MetricsMiddleware(collector))
+}
+
+// MetricsMiddleware counts the attempt as in flight until the response headers or an error arrive,
+// then observes its latency. A nil collector is replaced by NoopMetrics.
+func MetricsMiddleware(collector MetricsCollector) Middleware {
+	if collector == nil {
+		collector = NoopMetrics{}
+	}
+
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			host, method := req.URL.Host, req.Method
+
+			collector.IncInFlight(host, method)
+			start := time.Now()
+
+			response, err := next(req)
+
+			collector.DecInFlight(host, method)
+
+			code := 0
+
+			if err == nil {
+				code = response.StatusCode
+			}
+
+			collector.ObserveLatency(host, method, code, time.Since(start))
+
+			return response, err
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Package metrics provides a dependency-free room.MetricsCollector keeping totals in memory.
+//
+// To feed a Prometheus registry instead, implement room.MetricsCollector on top of client_golang vectors:
+//
+//	type promCollector struct {
+//		latency  *prometheus.HistogramVec // labels: host, method, code
+//		inFlight *prometheus.GaugeVec     // labels: host, method
+//	}
+//
+//	func (c promCollector) ObserveLatency(host, method string, code int, d time.Duration) {
+//		c.latency.WithLabelValues(host, method, strconv.Itoa(code)).Observe(d.Seconds())
+//	}
+//
+//	func (c promCollector) IncInFlight(host, method string) { c.inFlight.WithLabelValues(host, method).Inc() }
+//
+//	func (c promCollector) DecInFlight(host, method string) { c.inFlight.WithLabelValues(host, method).Dec() }
+//
+// register both vectors with prometheus.MustRegister and pass the collector to room.WithMetrics.
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+type Key struct {
+	Host   string
+	Method string
+	Code   int
+}
+
+type Stats struct {
+	Count int
+	Total time.Duration
+	Max   time.Duration
+}
+
+// Recorder aggregates latencies per host, method and status code and tracks the attempts in flight.
+type Recorder struct {
+	mu       sync.Mutex
+	stats    map[Key]Stats
+	inFlight map[Key]int
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{stats: map[Key]Stats{}, inFlight: map[Key]int{}}
+}
+
+func (r *Recorder) ObserveLatency(host, method string, code int, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := Key{Host: host, Method: method, Code: code}
+	stats := r.stats[key]
+
+	stats.Count++
+	stats.Total += d
+	stats.Max = max(stats.Max, d)
+
+	r.stats[key] = stats
+}
+
+func (r *Recorder) IncInFlight(host, method string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.inFlight[Key{Host: host, Method: method}]++
+}
+
+func (r *Recorder) DecInFlight(host, method string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.inFlight[Key{Host: host, Method: method}]--
+}
+
+// Stats returns a copy of the latencies observed so far.
+func (r *Recorder) Stats() map[Key]Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := make(map[Key]Stats, len(r.stats))
+
+	for key, value := range r.stats {
+		stats[key] = value
+	}
+
+	return stats
+}
+
+// InFlight returns the number of attempts to host with method currently waiting for a response.
+func (r *Recorder) InFlight(host, method string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.inFlight[Key{Host: host, Method: method}]
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/WEG-Technology/room"
+)
+
+func TestRecorder_WithMetrics(t *testing.T) {
+	recorder := NewRecorder()
+	inFlight := -1
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inFlight = recorder.InFlight(r.Host, r.Method)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	for i := 0; i < 2; i++ {
+		if _, err := room.NewRequest(server.URL, room.WithMethod(room.POST), room.WithMetrics(recorder)).Send(); err != nil {
+			t.Fatalf("Send() returned unexpected error: %v", err)
+		}
+	}
+
+	if inFlight != 1 || recorder.InFlight(host, "POST") != 0 {
+		t.Errorf("Recorder counted %d attempts in flight during the call and %d after, expected 1 and 0", inFlight, recorder.InFlight(host, "POST"))
+	}
+
+	stats := recorder.Stats()[Key{Host: host, Method: "POST", Code: http.StatusCreated}]
+	if stats.Count != 2 || stats.Total <= 0 || stats.Max > stats.Total {
+		t.Errorf("Recorder observed %+v, expected 2 latencies", stats)
+	}
+
+	_, _ = room.NewRequest("http://127.0.0.1:1", room.WithMetrics(recorder)).Send()
+	if recorder.Stats()[Key{Host: "127.0.0.1:1", Method: "GET"}].Count != 1 {
+		t.Error("Recorder did not observe the failed attempt with code 0")
+	}
+}
+
+func TestWithMetrics_Nil(t *testing.T) {
+	if _, err := room.NewRequest("http://127.0.0.1:1", room.WithMetrics(nil)).Send(); err == nil {
+		t.Error("Send() did not return the connection error")
+	}
+}
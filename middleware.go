@@ -0,0 +1,39 @@
+package room
+
+import "net/http"
+
+// RoundTripperFunc is a single round-trip step, it also satisfies http.RoundTripper.
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the round-trip made by Send. It may change the request, short-circuit the call or inspect the response.
+type Middleware func(next RoundTripperFunc) RoundTripperFunc
+
+// WithMiddleware appends middlewares to the request. The first one registered is the outermost one.
+// Middlewares run once per attempt when the request is retried.
+func WithMiddleware(middlewares ...Middleware) OptionRequest {
+	return func(request *Request) {
+		request.middlewares = append(request.middlewares, middlewares...)
+	}
+}
+
+func (r *Request) roundTrip(req *http.Request) (*http.Response, error) {
+	client, err := r.httpClient()
+
+	if err != nil {
+		closeReaders(req.Body)
+
+		return nil, err
+	}
+
+	next := RoundTripperFunc(client.Do)
+
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		next = r.middlewares[i](next)
+	}
+
+	return next(req)
+}
@@ -0,0 +1,48 @@
+package room
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequest_SendWithMiddleware(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("X-Trace")))
+	}))
+	defer server.Close()
+
+	var order []string
+	tag := func(name string) Middleware {
+		return func(next RoundTripperFunc) RoundTripperFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				req.Header.Set("X-Trace", req.Header.Get("X-Trace")+name)
+				return next(req)
+			}
+		}
+	}
+
+	response, err := NewRequest(server.URL, WithMiddleware(tag("a"), tag("b")), WithMiddleware(tag("c"))).Send()
+	if err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+	if string(response.Data) != "abc" || len(order) != 3 {
+		t.Errorf("middlewares ran as %v and sent %q, expected a, b, c", order, response.Data)
+	}
+}
+
+func TestRequest_SendWithShortCircuitMiddleware(t *testing.T) {
+	errBlocked := errors.New("blocked")
+	block := func(next RoundTripperFunc) RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			return nil, errBlocked
+		}
+	}
+
+	_, err := NewRequest("http://127.0.0.1:1", WithMiddleware(block)).Send()
+	if !errors.Is(err, errBlocked) {
+		t.Errorf("Send() returned %v, expected the middleware error", err)
+	}
+}
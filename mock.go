@@ -0,0 +1,188 @@
+package room
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+)
+
+var ErrNoMockRoute = errors.New("no mock route matches the request")
+
+// MockTransport answers requests with registered routes instead of the network, pass it with WithTransport.
+// It is safe for concurrent use and records every request it receives.
+type MockTransport struct {
+	mu     sync.Mutex
+	routes []*MockRoute
+	calls  []MockCall
+}
+
+// MockCall is a request received by a MockTransport along with its body.
+type MockCall struct {
+	Request *http.Request
+	Body    []byte
+}
+
+// MockRoute answers the requests matching its method and URL pattern.
+type MockRoute struct {
+	method  string
+	pattern string
+	handler func(req *http.Request) (*http.Response, error)
+	once    bool
+	used    bool
+}
+
+func NewMockTransport() *MockTransport {
+	return &MockTransport{}
+}
+
+// On registers a route answering 200 with an empty body until told otherwise. Routes are matched in registration order.
+// An empty or "*" method matches any method. A pattern starting with "/" is matched against the path, any other pattern
+// against scheme://host/path, both with path.Match so "*" matches within a segment, e.g. "/users/*".
+func (m *MockTransport) On(method, pattern string) *MockRoute {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	route := &MockRoute{method: method, pattern: pattern}
+	route.Reply(http.StatusOK, "")
+
+	m.routes = append(m.routes, route)
+
+	return route
+}
+
+// Reply answers with status and body.
+func (r *MockRoute) Reply(status int, body string, headers ...http.Header) *MockRoute {
+	return r.Handle(func(req *http.Request) (*http.Response, error) {
+		response := bufferedResponse(req, status, []byte(body))
+
+		for _, header := range headers {
+			for key, values := range header {
+				response.Header[key] = values
+			}
+		}
+
+		return response, nil
+	})
+}
+
+// ReplyJSON answers with status and v marshalled as JSON.
+func (r *MockRoute) ReplyJSON(status int, v any) *MockRoute {
+	return r.Handle(func(req *http.Request) (*http.Response, error) {
+		data, err := json.Marshal(v)
+
+		if err != nil {
+			return nil, err
+		}
+
+		response := bufferedResponse(req, status, data)
+		response.Header.Set(headerKeyContentType, headerValueApplicationJson)
+
+		return response, nil
+	})
+}
+
+// Fail answers with err, as a failing transport would.
+func (r *MockRoute) Fail(err error) *MockRoute {
+	return r.Handle(func(*http.Request) (*http.Response, error) {
+		return nil, err
+	})
+}
+
+func (r *MockRoute) Handle(handler func(req *http.Request) (*http.Response, error)) *MockRoute {
+	r.handler = handler
+
+	return r
+}
+
+// Once makes the route answer a single request, the following ones fall through to the next matching routes.
+func (r *MockRoute) Once() *MockRoute {
+	r.once = true
+
+	return r
+}
+
+func (r *MockRoute) matches(req *http.Request) bool {
+	if r.once && r.used {
+		return false
+	}
+
+	if r.method != "" && r.method != "*" && !strings.EqualFold(r.method, req.Method) {
+		return false
+	}
+
+	target := req.URL.Scheme + "://" + req.URL.Host + req.URL.Path
+
+	if strings.HasPrefix(r.pattern, "/") {
+		target = req.URL.Path
+	}
+
+	matched, _ := path.Match(r.pattern, target)
+
+	return matched
+}
+
+func (m *MockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+
+	if req.Body != nil {
+		var err error
+
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	m.mu.Lock()
+
+	m.calls = append(m.calls, MockCall{Request: req, Body: body})
+
+	var route *MockRoute
+
+	for _, candidate := range m.routes {
+		if candidate.matches(req) {
+			route, candidate.used = candidate, true
+
+			break
+		}
+	}
+
+	m.mu.Unlock()
+
+	if route == nil {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoMockRoute, req.Method, req.URL)
+	}
+
+	return route.handler(req)
+}
+
+// Calls returns the requests received so far, in order.
+func (m *MockTransport) Calls() []MockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]MockCall(nil), m.calls...)
+}
+
+// bufferedResponse answers req with an in-memory body.
+func bufferedResponse(req *http.Request, status int, body []byte) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
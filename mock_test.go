@@ -0,0 +1,51 @@
+package room
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestMockTransport(t *testing.T) {
+	mock := NewMockTransport()
+	mock.On("GET", "/users/*").ReplyJSON(http.StatusOK, map[string]string{"name": "room"})
+	mock.On("POST", "https://api.example.com/users").Reply(http.StatusCreated, "created", http.Header{"Location": {"/users/1"}})
+
+	var user struct{ Name string }
+	response, err := NewRequest("https://api.example.com/users/1", WithTransport(mock)).Send()
+	if err != nil || response.JSON(&user) != nil || user.Name != "room" {
+		t.Errorf("MockTransport answered (%s, %v), expected the JSON route", response.Data, err)
+	}
+
+	response, err = NewRequest("https://api.example.com/users", WithMethod(POST), WithBody(JSONBody("payload")), WithTransport(mock)).Send()
+	if err != nil || response.StatusCode != http.StatusCreated || response.Header.Get("Location") != "/users/1" {
+		t.Errorf("MockTransport answered (%d, %v), expected the POST route", response.StatusCode, err)
+	}
+
+	calls := mock.Calls()
+	if len(calls) != 2 || calls[1].Request.Method != "POST" || string(calls[1].Body) != "\"payload\"\n" {
+		t.Errorf("MockTransport recorded %d calls, expected the POST body to be captured", len(calls))
+	}
+
+	if _, err = NewRequest("https://api.example.com/orders", WithTransport(mock)).Send(); !errors.Is(err, ErrNoMockRoute) {
+		t.Errorf("Send() returned %v for an unregistered route, expected ErrNoMockRoute", err)
+	}
+}
+
+func TestMockTransport_OnceAndFail(t *testing.T) {
+	errDown := errors.New("connection refused")
+
+	mock := NewMockTransport()
+	mock.On("", "/health").Fail(errDown).Once()
+	mock.On("", "/health").Reply(http.StatusOK, "up")
+
+	if _, err := NewRequest("http://localhost/health", WithTransport(mock)).Send(); !errors.Is(err, errDown) {
+		t.Errorf("Send() returned %v, expected the mocked error", err)
+	}
+
+	response, err := NewRequest("http://localhost/health", WithTransport(mock), WithRetry(2, ConstantBackoff(time.Millisecond))).Send()
+	if err != nil || string(response.Data) != "up" {
+		t.Errorf("Send() returned (%s, %v), expected the route registered after the one-shot failure", response.Data, err)
+	}
+}
@@ -0,0 +1,205 @@
+package room
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MultipartBody streams a multipart/form-data request body made of fields and files.
+// Files are copied straight from their readers while the transport sends the request. To send them again on a retry
+// or a redirect, readers are rewound or replayed like a ReaderBody, up to MaxBodyReplaySize.
+type MultipartBody struct {
+	boundary string
+	parts    []multipartPart
+}
+
+type multipartPart struct {
+	name     string
+	fileName string
+	value    string
+	reader   *readerBody
+	// path is opened on every Parse, the file of a part added by MultipartForm.WithFileFromPath.
+	path string
+	// detect derives the content type of the file from its name or content instead of sending application/octet-stream.
+	detect bool
+}
+
+func NewMultipartBody() *MultipartBody {
+	return &MultipartBody{boundary: multipart.NewWriter(nil).Boundary()}
+}
+
+func (b *MultipartBody) AddField(name, value string) *MultipartBody {
+	b.parts = append(b.parts, multipartPart{name: name, value: value})
+
+	return b
+}
+
+func (b *MultipartBody) AddFile(fieldName, fileName string, r io.Reader) *MultipartBody {
+	b.parts = append(b.parts, multipartPart{name: fieldName, fileName: fileName, reader: &readerBody{reader: r}})
+
+	return b
+}
+
+// ContentType carries the boundary every Parse call writes with.
+func (b *MultipartBody) ContentType() string {
+	return headerValueMultipartFormData + "; boundary=" + b.boundary
+}
+
+func (b *MultipartBody) Parse() (io.Reader, error) {
+	pr, pw := io.Pipe()
+
+	go func() {
+		_ = pw.CloseWithError(b.write(pw))
+	}()
+
+	return pr, nil
+}
+
+func (b *MultipartBody) sharesSource() bool {
+	for _, part := range b.parts {
+		if part.reader != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *MultipartBody) write(w io.Writer) error {
+	writer := multipart.NewWriter(w)
+
+	if err := writer.SetBoundary(b.boundary); err != nil {
+		return err
+	}
+
+	for _, part := range b.parts {
+		if part.reader == nil && part.path == "" {
+			if err := writer.WriteField(part.name, part.value); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := part.writeFile(writer); err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+func (p multipartPart) writeFile(writer *multipart.Writer) error {
+	var reader io.Reader
+
+	if p.path == "" {
+		var err error
+
+		if reader, err = p.reader.Parse(); err != nil {
+			return err
+		}
+	} else {
+		file, err := os.Open(p.path)
+
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+
+		reader = file
+	}
+
+	contentType := headerValueOctetStream
+
+	if p.detect {
+		if contentType = mime.TypeByExtension(filepath.Ext(p.fileName)); contentType == "" {
+			buffered := bufio.NewReaderSize(reader, sniffSize)
+			head, _ := buffered.Peek(sniffSize)
+			contentType, reader = http.DetectContentType(head), buffered
+		}
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, quoteEscaper.Replace(p.name), quoteEscaper.Replace(p.fileName)))
+	header.Set(headerKeyContentType, contentType)
+
+	fileWriter, err := writer.CreatePart(header)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(fileWriter, reader)
+
+	return err
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// MultipartForm builds a multipart/form-data body of fields and files, the content type of each file being derived
+// from its extension or sniffed from its first bytes. Several files or values can be sent under the same field name.
+//
+//	body := NewMultipartForm().
+//		WithField("title", "report").
+//		WithFileFromPath("attachments", "report.pdf").
+//		WithFileFromPath("attachments", "summary.csv")
+//
+// Files added by path are opened on every Parse, so they are sent again on a retry, readers are rewound or replayed from memory
+// like a ReaderBody.
+type MultipartForm struct {
+	body *MultipartBody
+	err  error
+}
+
+func NewMultipartForm() *MultipartForm {
+	return &MultipartForm{body: NewMultipartBody()}
+}
+
+func (f *MultipartForm) WithField(name, value string) *MultipartForm {
+	f.body.AddField(name, value)
+
+	return f
+}
+
+// WithFileFromPath adds the file at path, named after its base name. A missing file is reported by Parse.
+func (f *MultipartForm) WithFileFromPath(fieldName, path string) *MultipartForm {
+	if info, err := os.Stat(path); err != nil {
+		f.err = err
+	} else if !info.Mode().IsRegular() {
+		f.err = fmt.Errorf("multipart file %s: not a regular file", path)
+	}
+
+	f.body.parts = append(f.body.parts, multipartPart{name: fieldName, fileName: filepath.Base(path), path: path, detect: true})
+
+	return f
+}
+
+func (f *MultipartForm) WithFileReader(fieldName, fileName string, r io.Reader) *MultipartForm {
+	f.body.parts = append(f.body.parts, multipartPart{name: fieldName, fileName: fileName, reader: &readerBody{reader: r}, detect: true})
+
+	return f
+}
+
+func (f *MultipartForm) ContentType() string {
+	return f.body.ContentType()
+}
+
+func (f *MultipartForm) sharesSource() bool { return f.body.sharesSource() }
+
+// Parse returns the first error met while building the form, e.g. a file that WithFileFromPath could not find.
+func (f *MultipartForm) Parse() (io.Reader, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return f.body.Parse()
+}
@@ -0,0 +1,144 @@
+package room
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMultipartBody_Parse(t *testing.T) {
+	body := NewMultipartBody().
+		AddField("name", "room").
+		AddFile("file", "hello.txt", strings.NewReader("hello world"))
+
+	mediaType, params, err := mime.ParseMediaType(body.ContentType())
+	if err != nil || mediaType != headerValueMultipartFormData {
+		t.Fatalf("MultipartBody ContentType() returned %s, expected multipart/form-data with a boundary", body.ContentType())
+	}
+
+	reader, err := body.Parse()
+	if err != nil {
+		t.Fatalf("MultipartBody Parse() returned unexpected error: %v", err)
+	}
+
+	form, err := multipart.NewReader(reader, params["boundary"]).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatalf("MultipartBody Parse() produced an unreadable body: %v", err)
+	}
+
+	if values := form.Value["name"]; len(values) != 1 || values[0] != "room" {
+		t.Errorf("MultipartBody field name is %v, expected [room]", values)
+	}
+
+	files := form.File["file"]
+	if len(files) != 1 || files[0].Filename != "hello.txt" {
+		t.Fatalf("MultipartBody file parts are %v, expected hello.txt", files)
+	}
+
+	f, _ := files[0].Open()
+	content, _ := io.ReadAll(f)
+	if string(content) != "hello world" {
+		t.Errorf("MultipartBody file content is %q, expected %q", content, "hello world")
+	}
+}
+
+func TestMultipartForm_Parse(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.pdf")
+	_ = os.WriteFile(path, []byte("%PDF"), 0o600)
+
+	body := NewMultipartForm().
+		WithField("title", "report").
+		WithField("tags", "a").
+		WithField("tags", "b").
+		WithFileFromPath("attachments", path).
+		WithFileReader("attachments", "image", strings.NewReader("\x89PNG\r\n\x1a\n")).
+		WithFileReader("attachments", "notes.json", strings.NewReader("{}"))
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		_, params, _ := mime.ParseMediaType(body.ContentType())
+		reader, err := body.Parse()
+		if err != nil {
+			t.Fatalf("MultipartForm Parse() returned unexpected error: %v", err)
+		}
+
+		form, err := multipart.NewReader(reader, params["boundary"]).ReadForm(1 << 20)
+		if err != nil {
+			t.Fatalf("MultipartForm Parse() produced an unreadable body: %v", err)
+		}
+
+		if tags := form.Value["tags"]; len(form.Value["title"]) != 1 || len(tags) != 2 || tags[1] != "b" {
+			t.Errorf("MultipartForm fields are %v, expected title and two tags", form.Value)
+		}
+
+		files := form.File["attachments"]
+		if attempt == 2 {
+			// The readers were consumed by the first attempt, the file is opened again.
+			if len(files) != 3 || files[0].Size != 4 {
+				t.Errorf("MultipartForm second Parse() sent %d files, expected the file read again", len(files))
+			}
+
+			continue
+		}
+
+		expected := map[string]string{"report.pdf": "application/pdf", "image": "image/png", "notes.json": "application/json"}
+		if len(files) != 3 {
+			t.Fatalf("MultipartForm file parts are %v, expected 3 files under attachments", files)
+		}
+
+		for _, file := range files {
+			if contentType := file.Header.Get(headerKeyContentType); contentType != expected[file.Filename] {
+				t.Errorf("MultipartForm file %s has content type %s, expected %s", file.Filename, contentType, expected[file.Filename])
+			}
+		}
+
+		f, _ := files[0].Open()
+		if content, _ := io.ReadAll(f); string(content) != "%PDF" {
+			t.Errorf("MultipartForm file content is %q, expected %q", content, "%PDF")
+		}
+	}
+
+	if _, err := NewMultipartForm().WithFileFromPath("file", filepath.Join(dir, "missing")).Parse(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("MultipartForm Parse() returned %v for a missing file, expected os.ErrNotExist", err)
+	}
+}
+
+func TestRequest_SendRetriesMultipartReader(t *testing.T) {
+	var attempts int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("file")
+
+		if attempts++; err != nil || attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		content, _ := io.ReadAll(file)
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+
+	pr, pw := io.Pipe()
+
+	go func() {
+		_, _ = io.WriteString(pw, "streamed once")
+		_ = pw.Close()
+	}()
+
+	response, err := NewRequest(server.URL, WithMethod(POST),
+		WithBody(NewMultipartBody().AddFile("file", "hello.txt", pr)),
+		WithRetryOnStatus(http.StatusServiceUnavailable), WithRetry(2, nil)).Send()
+
+	if body, _ := response.String(); err != nil || attempts != 2 || body != "streamed once" {
+		t.Errorf("Send() returned (%s, %v) after %d attempts, expected the file part replayed on the retry", body, err, attempts)
+	}
+}
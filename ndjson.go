@@ -0,0 +1,129 @@
+package room
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// JSONLineError reports a line of a newline-delimited JSON body that could not be decoded, Line starts at 1.
+type JSONLineError struct {
+	Line int
+	Err  error
+}
+
+func (e *JSONLineError) Error() string {
+	return fmt.Sprintf("ndjson line %d: %v", e.Line, e.Err)
+}
+
+func (e *JSONLineError) Unwrap() error {
+	return e.Err
+}
+
+// JSONLineScanner reads newline-delimited JSON, one value per line, from a response body without buffering it whole:
+//
+//	scanner := response.NDJSON()
+//	defer scanner.Close()
+//
+//	for scanner.Next() {
+//		var entry LogEntry
+//		if err := scanner.Decode(&entry); err != nil {
+//			// the line is skipped, err is a *JSONLineError
+//		}
+//	}
+//
+//	err = scanner.Err()
+type JSONLineScanner struct {
+	response Response
+	reader   *bufio.Reader
+	raw      json.RawMessage
+	line     int
+	err      error
+}
+
+// NDJSON scans the body as newline-delimited JSON, application/x-ndjson or JSON Lines. Send the request WithStream
+// to get the values as they arrive, the scanner stops with the error of the request context once it is cancelled.
+func (r Response) NDJSON() *JSONLineScanner {
+	reader, err := r.bodyReader()
+
+	return &JSONLineScanner{response: r, reader: bufio.NewReader(reader), err: err}
+}
+
+// Next reads the next non-blank line, it returns false at the end of the body or on a read error.
+func (s *JSONLineScanner) Next() bool {
+	for s.err == nil {
+		line, err := s.reader.ReadBytes('\n')
+
+		if len(line) > 0 {
+			s.line++
+		}
+
+		if err != nil {
+			s.err = err
+		}
+
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			s.raw = line
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// Raw returns the current line, valid until the next call to Next.
+func (s *JSONLineScanner) Raw() json.RawMessage {
+	return s.raw
+}
+
+// Line returns the number of the current line.
+func (s *JSONLineScanner) Line() int {
+	return s.line
+}
+
+// Decode unmarshals the current line into v, a decoding failure is a *JSONLineError and does not stop the scanner.
+func (s *JSONLineScanner) Decode(v any) error {
+	if err := json.Unmarshal(s.raw, v); err != nil {
+		return &JSONLineError{Line: s.line, Err: err}
+	}
+
+	return nil
+}
+
+// Err returns the error that stopped the scanner, nil when the body ended normally.
+func (s *JSONLineScanner) Err() error {
+	if errors.Is(s.err, io.EOF) {
+		return nil
+	}
+
+	return s.err
+}
+
+func (s *JSONLineScanner) Close() error {
+	return s.response.Close()
+}
+
+// EachJSON calls fn with every line of a newline-delimited JSON body and closes the body.
+// It stops at the first line that is not valid JSON with a *JSONLineError, or at the first error returned by fn.
+func (r Response) EachJSON(fn func(raw json.RawMessage) error) error {
+	scanner := r.NDJSON()
+	defer scanner.Close()
+
+	for scanner.Next() {
+		if !json.Valid(scanner.Raw()) {
+			var v any
+
+			return scanner.Decode(&v)
+		}
+
+		if err := fn(scanner.Raw()); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,83 @@
+package room
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResponse_NDJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerKeyContentType, "application/x-ndjson")
+		_, _ = w.Write([]byte("{\"id\":1}\n\n{\"id\":\"two\"}\r\n{\"id\":3}"))
+	}))
+	defer server.Close()
+
+	response, err := NewRequest(server.URL, WithStream()).Send()
+	if err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+
+	scanner := response.NDJSON()
+	defer scanner.Close()
+
+	var ids []int
+	var lineErr *JSONLineError
+
+	for scanner.Next() {
+		var entry struct{ ID int }
+
+		if err = scanner.Decode(&entry); err != nil {
+			if !errors.As(err, &lineErr) || lineErr.Line != 3 {
+				t.Errorf("Decode() returned %v, expected a *JSONLineError for line 3", err)
+			}
+
+			continue
+		}
+
+		ids = append(ids, entry.ID)
+	}
+
+	if scanner.Err() != nil || len(ids) != 2 || ids[0] != 1 || ids[1] != 3 {
+		t.Errorf("NDJSON() decoded %v with %v, expected [1 3]", ids, scanner.Err())
+	}
+}
+
+func TestResponse_EachJSON(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("{\"id\":1}\n"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	response, err := NewRequest(server.URL, WithStream(), WithContext(ctx)).Send()
+	if err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+
+	var lines int
+
+	err = response.EachJSON(func(raw json.RawMessage) error {
+		lines++
+		cancel()
+
+		return nil
+	})
+
+	if lines != 1 || !errors.Is(err, context.Canceled) {
+		t.Errorf("EachJSON() read %d lines and returned %v, expected to stop with context.Canceled after 1", lines, err)
+	}
+
+	response = Response{Data: []byte("{\"id\":1}\nnot json\n")}
+
+	if err = response.EachJSON(func(json.RawMessage) error { return nil }); !errors.As(err, new(*JSONLineError)) {
+		t.Errorf("EachJSON() returned %v, expected a *JSONLineError", err)
+	}
+}
@@ -0,0 +1,191 @@
+package ntlm
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+var signature = []byte("NTLMSSP\x00")
+
+const (
+	negotiateUnicode                 = 0x00000001
+	requestTarget                    = 0x00000004
+	negotiateNTLM                    = 0x00000200
+	negotiateAlwaysSign              = 0x00008000
+	negotiateExtendedSessionSecurity = 0x00080000
+	negotiateTargetInfo              = 0x00800000
+	negotiate128                     = 0x20000000
+	negotiate56                      = 0x80000000
+
+	negotiateFlags = negotiateUnicode | requestTarget | negotiateNTLM | negotiateAlwaysSign |
+		negotiateExtendedSessionSecurity | negotiateTargetInfo | negotiate128 | negotiate56
+
+	avIDEOL       = 0
+	avIDTimestamp = 7
+)
+
+var ErrInvalidChallenge = errors.New("invalid NTLM challenge message")
+
+// negotiateMessage is the type 1 message opening the handshake, without domain and workstation.
+func negotiateMessage() []byte {
+	msg := make([]byte, 32)
+
+	copy(msg, signature)
+	binary.LittleEndian.PutUint32(msg[8:], 1)
+	binary.LittleEndian.PutUint32(msg[12:], negotiateFlags)
+
+	return msg
+}
+
+// challengeMessage is the type 2 message the server answers with.
+type challengeMessage struct {
+	flags      uint32
+	challenge  [8]byte
+	targetInfo []byte
+}
+
+func parseChallengeMessage(msg []byte) (challengeMessage, error) {
+	var challenge challengeMessage
+
+	if len(msg) < 32 || !bytes.Equal(msg[:8], signature) || binary.LittleEndian.Uint32(msg[8:]) != 2 {
+		return challenge, ErrInvalidChallenge
+	}
+
+	challenge.flags = binary.LittleEndian.Uint32(msg[20:])
+	copy(challenge.challenge[:], msg[24:32])
+
+	if len(msg) >= 48 {
+		length := int(binary.LittleEndian.Uint16(msg[40:]))
+		offset := int(binary.LittleEndian.Uint32(msg[44:]))
+
+		if offset+length > len(msg) {
+			return challenge, ErrInvalidChallenge
+		}
+
+		challenge.targetInfo = msg[offset : offset+length]
+	}
+
+	return challenge, nil
+}
+
+// timestamp returns the MsvAvTimestamp of the target info, if the server sent one.
+func (c challengeMessage) timestamp() ([]byte, bool) {
+	info := c.targetInfo
+
+	for len(info) >= 4 {
+		id := binary.LittleEndian.Uint16(info)
+		length := int(binary.LittleEndian.Uint16(info[2:]))
+
+		if id == avIDEOL || len(info) < 4+length {
+			break
+		}
+
+		if id == avIDTimestamp && length == 8 {
+			return info[4:12], true
+		}
+
+		info = info[4+length:]
+	}
+
+	return nil, false
+}
+
+type credentials struct {
+	domain   string
+	username string
+	password string
+}
+
+// ntowfv2 is the NTLMv2 response key, HMAC-MD5 keyed by the MD4 of the password over the upper-cased user and the domain.
+func (c credentials) ntowfv2() []byte {
+	hash := md4(utf16le(c.password))
+
+	return hmacMD5(hash[:], utf16le(strings.ToUpper(c.username)+c.domain))
+}
+
+// authenticateMessage is the type 3 message answering challenge with NTLMv2 responses.
+func authenticateMessage(creds credentials, challenge challengeMessage, clientChallenge [8]byte, now time.Time) []byte {
+	timestamp, ok := challenge.timestamp()
+
+	if !ok {
+		timestamp = fileTime(now)
+	}
+
+	lm, nt := responses(creds.ntowfv2(), challenge.challenge, clientChallenge, timestamp, challenge.targetInfo)
+
+	flags := challenge.flags & negotiateFlags
+
+	domain, user := []byte(creds.domain), []byte(creds.username)
+
+	if flags&negotiateUnicode != 0 {
+		domain, user = utf16le(creds.domain), utf16le(creds.username)
+	}
+
+	const headerSize = 64
+
+	msg := make([]byte, headerSize)
+
+	copy(msg, signature)
+	binary.LittleEndian.PutUint32(msg[8:], 3)
+
+	for _, field := range []struct {
+		offset  int
+		payload []byte
+	}{{12, lm}, {20, nt}, {28, domain}, {36, user}, {44, nil}, {52, nil}} {
+		binary.LittleEndian.PutUint16(msg[field.offset:], uint16(len(field.payload)))
+		binary.LittleEndian.PutUint16(msg[field.offset+2:], uint16(len(field.payload)))
+		binary.LittleEndian.PutUint32(msg[field.offset+4:], uint32(len(msg)))
+
+		msg = append(msg, field.payload...)
+	}
+
+	binary.LittleEndian.PutUint32(msg[60:], flags)
+
+	return msg
+}
+
+// responses computes the LMv2 and NTLMv2 challenge responses.
+func responses(key []byte, serverChallenge, clientChallenge [8]byte, timestamp, targetInfo []byte) ([]byte, []byte) {
+	temp := []byte{1, 1, 0, 0, 0, 0, 0, 0}
+	temp = append(temp, timestamp...)
+	temp = append(temp, clientChallenge[:]...)
+	temp = append(temp, 0, 0, 0, 0)
+	temp = append(temp, targetInfo...)
+	temp = append(temp, 0, 0, 0, 0)
+
+	proof := hmacMD5(key, append(serverChallenge[:], temp...))
+	lm := append(hmacMD5(key, append(serverChallenge[:], clientChallenge[:]...)), clientChallenge[:]...)
+
+	return lm, append(proof, temp...)
+}
+
+func hmacMD5(key, data []byte) []byte {
+	mac := hmac.New(md5.New, key)
+	mac.Write(data)
+
+	return mac.Sum(nil)
+}
+
+func utf16le(s string) []byte {
+	encoded := utf16.Encode([]rune(s))
+	b := make([]byte, 2*len(encoded))
+
+	for i, r := range encoded {
+		binary.LittleEndian.PutUint16(b[2*i:], r)
+	}
+
+	return b
+}
+
+// fileTime encodes t as a Windows FILETIME, 100ns intervals since 1601.
+func fileTime(t time.Time) []byte {
+	const epochDelta = 116444736000000000
+
+	return binary.LittleEndian.AppendUint64(nil, uint64(t.UnixNano()/100+epochDelta))
+}
@@ -0,0 +1,189 @@
+// Package ntlm authenticates room requests against Windows services with NTLMv2, for intranet APIs behind IIS for instance.
+// NTLM authenticates a connection rather than a request, so every request runs the negotiate, challenge and authenticate
+// exchange over a connection of its own, which is closed once its response body is.
+package ntlm
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/WEG-Technology/room"
+)
+
+const (
+	headerKeyAuthorization   = "Authorization"
+	headerKeyWWWAuthenticate = "WWW-Authenticate"
+	scheme                   = "NTLM"
+)
+
+// WithNTLMAuth sends the request with a Transport authenticating as domain\username.
+// Transport options such as room.WithTLSConfig do not apply to it, configure Transport.Base instead.
+func WithNTLMAuth(domain, username, password string) room.OptionRequest {
+	return room.WithTransport(&Transport{Domain: domain, Username: username, Password: password})
+}
+
+// Transport runs the NTLM handshake for every request it sends.
+type Transport struct {
+	Domain   string
+	Username string
+	Password string
+	// Base is cloned for every handshake, a clone of http.DefaultTransport when nil.
+	Base *http.Transport
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	transport := t.connection()
+
+	response, err := transport.RoundTrip(withAuthorization(req, body, negotiateMessage()))
+
+	if err != nil {
+		transport.CloseIdleConnections()
+
+		return nil, err
+	}
+
+	challenge, ok := challengeFrom(response)
+
+	if !ok {
+		return closingResponse(response, transport), nil
+	}
+
+	message, err := parseChallengeMessage(challenge)
+
+	if err != nil {
+		_ = response.Body.Close()
+		transport.CloseIdleConnections()
+
+		return nil, err
+	}
+
+	// Draining the challenge response hands its connection back for the authenticate message.
+	_, _ = io.Copy(io.Discard, response.Body)
+	_ = response.Body.Close()
+
+	var clientChallenge [8]byte
+
+	if _, err = io.ReadFull(rand.Reader, clientChallenge[:]); err != nil {
+		transport.CloseIdleConnections()
+
+		return nil, fmt.Errorf("generate NTLM client challenge: %w", err)
+	}
+
+	creds := credentials{domain: t.Domain, username: t.Username, password: t.Password}
+	authenticate := authenticateMessage(creds, message, clientChallenge, time.Now())
+
+	response, err = transport.RoundTrip(withAuthorization(req, body, authenticate))
+
+	if err != nil {
+		transport.CloseIdleConnections()
+
+		return nil, err
+	}
+
+	return closingResponse(response, transport), nil
+}
+
+// connection returns a transport limited to a single connection per host, so the three messages share it.
+func (t *Transport) connection() *http.Transport {
+	base := t.Base
+
+	if base == nil {
+		base = http.DefaultTransport.(*http.Transport)
+	}
+
+	transport := base.Clone()
+	transport.MaxConnsPerHost = 1
+	transport.DisableKeepAlives = false
+
+	// HTTP/2 multiplexes requests of several users over a connection, NTLM requires HTTP/1.1.
+	transport.ForceAttemptHTTP2 = false
+	transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+
+	if transport.TLSClientConfig != nil {
+		transport.TLSClientConfig.NextProtos = slices.DeleteFunc(slices.Clone(transport.TLSClientConfig.NextProtos), func(proto string) bool {
+			return proto == "h2"
+		})
+	}
+
+	return transport
+}
+
+// readBody buffers the body so it can be sent with each message of the handshake.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	defer req.Body.Close()
+
+	return io.ReadAll(req.Body)
+}
+
+func withAuthorization(req *http.Request, body []byte, message []byte) *http.Request {
+	clone := req.Clone(req.Context())
+	clone.Header.Set(headerKeyAuthorization, scheme+" "+base64.StdEncoding.EncodeToString(message))
+
+	if body != nil {
+		clone.Body = io.NopCloser(bytes.NewReader(body))
+		clone.ContentLength = int64(len(body))
+		clone.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	return clone
+}
+
+// challengeFrom extracts the challenge message of a 401 answering the negotiate message.
+func challengeFrom(response *http.Response) ([]byte, bool) {
+	if response.StatusCode != http.StatusUnauthorized {
+		return nil, false
+	}
+
+	for _, value := range response.Header.Values(headerKeyWWWAuthenticate) {
+		token, found := strings.CutPrefix(value, scheme+" ")
+
+		if !found {
+			continue
+		}
+
+		if message, err := base64.StdEncoding.DecodeString(strings.TrimSpace(token)); err == nil {
+			return message, true
+		}
+	}
+
+	return nil, false
+}
+
+// closingResponse closes the connection of the handshake along with the response body.
+func closingResponse(response *http.Response, transport *http.Transport) *http.Response {
+	response.Body = &closingBody{ReadCloser: response.Body, transport: transport}
+
+	return response
+}
+
+type closingBody struct {
+	io.ReadCloser
+	transport *http.Transport
+}
+
+func (b *closingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.transport.CloseIdleConnections()
+
+	return err
+}
@@ -0,0 +1,120 @@
+package ntlm
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/WEG-Technology/room"
+)
+
+// MS-NLMP 4.2.4 NTLMv2 authentication test values.
+var (
+	testCredentials     = credentials{domain: "Domain", username: "User", password: "Password"}
+	testServerChallenge = [8]byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}
+	testClientChallenge = [8]byte{0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa}
+	testTargetInfo      = append(append(append([]byte{0x02, 0x00, 0x0c, 0x00}, utf16le("Domain")...),
+		append([]byte{0x01, 0x00, 0x0c, 0x00}, utf16le("Server")...)...), 0, 0, 0, 0)
+)
+
+func TestResponses(t *testing.T) {
+	key := testCredentials.ntowfv2()
+	if hex.EncodeToString(key) != "0c868a403bfd7a93a3001ef22ef02e3f" {
+		t.Errorf("ntowfv2() returned %x, expected 0c868a403bfd7a93a3001ef22ef02e3f", key)
+	}
+
+	lm, nt := responses(key, testServerChallenge, testClientChallenge, make([]byte, 8), testTargetInfo)
+
+	if hex.EncodeToString(lm) != "86c35097ac9cec102554764a57cccc19aaaaaaaaaaaaaaaa" {
+		t.Errorf("responses() returned the LMv2 response %x, expected 86c35097ac9cec102554764a57cccc19aaaaaaaaaaaaaaaa", lm)
+	}
+	if hex.EncodeToString(nt[:16]) != "68cd0ab851e51c96aabc927bebef6a1c" {
+		t.Errorf("responses() returned the NTProofStr %x, expected 68cd0ab851e51c96aabc927bebef6a1c", nt[:16])
+	}
+}
+
+func challengeMessageBytes() []byte {
+	msg := make([]byte, 48)
+
+	copy(msg, signature)
+	binary.LittleEndian.PutUint32(msg[8:], 2)
+	binary.LittleEndian.PutUint32(msg[20:], negotiateFlags)
+	copy(msg[24:], testServerChallenge[:])
+	binary.LittleEndian.PutUint16(msg[40:], uint16(len(testTargetInfo)))
+	binary.LittleEndian.PutUint16(msg[42:], uint16(len(testTargetInfo)))
+	binary.LittleEndian.PutUint32(msg[44:], 48)
+
+	return append(msg, testTargetInfo...)
+}
+
+// field returns the payload of the security buffer at offset of an authenticate message.
+func field(msg []byte, offset int) []byte {
+	length := int(binary.LittleEndian.Uint16(msg[offset:]))
+	start := int(binary.LittleEndian.Uint32(msg[offset+4:]))
+
+	return msg[start : start+length]
+}
+
+func TestWithNTLMAuth(t *testing.T) {
+	var mu sync.Mutex
+	challenged := map[string]bool{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		body, _ := io.ReadAll(r.Body)
+		msg, _ := base64.StdEncoding.DecodeString(string(bytes.TrimPrefix([]byte(r.Header.Get(headerKeyAuthorization)), []byte("NTLM "))))
+
+		switch {
+		case len(msg) >= 12 && binary.LittleEndian.Uint32(msg[8:]) == 1:
+			challenged[r.RemoteAddr] = true
+			w.Header().Set(headerKeyWWWAuthenticate, "NTLM "+base64.StdEncoding.EncodeToString(challengeMessageBytes()))
+			w.WriteHeader(http.StatusUnauthorized)
+		case len(msg) >= 64 && binary.LittleEndian.Uint32(msg[8:]) == 3 && challenged[r.RemoteAddr]:
+			nt := field(msg, 20)
+			key := credentials{domain: "CORP", username: "alice", password: "s3cret"}.ntowfv2()
+
+			if !bytes.Equal(field(msg, 28), utf16le("CORP")) || !bytes.Equal(field(msg, 36), utf16le("alice")) ||
+				!bytes.Equal(nt[:16], hmacMD5(key, append(testServerChallenge[:], nt[16:]...))) {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+
+			_, _ = w.Write(append([]byte("authenticated "), body...))
+		default:
+			w.Header().Set(headerKeyWWWAuthenticate, "NTLM")
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	response, err := room.NewRequest(server.URL, room.WithMethod(room.POST), room.WithBody(room.JSONBody("payload")),
+		WithNTLMAuth("CORP", "alice", "s3cret"), room.WithTimeout(5*time.Second)).Send()
+
+	if err != nil || response.StatusCode != http.StatusOK || string(bytes.TrimSpace(response.Data)) != `authenticated "payload"` {
+		t.Errorf("Send() returned (%d %s, %v), expected to be authenticated over a single connection", response.StatusCode, response.Data, err)
+	}
+
+	if response, _ = room.NewRequest(server.URL, WithNTLMAuth("CORP", "alice", "wrong")).Send(); response.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Send() with a wrong password returned %d, expected 401", response.StatusCode)
+	}
+
+	defer func(reader io.Reader) { rand.Reader = reader }(rand.Reader)
+	rand.Reader = iotest.ErrReader(errors.New("no entropy"))
+
+	if _, err = room.NewRequest(server.URL, WithNTLMAuth("CORP", "alice", "s3cret")).Send(); err == nil {
+		t.Error("Send() returned no error without a random client challenge")
+	}
+}
@@ -0,0 +1,139 @@
+package room
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const headerKeyLink = "Link"
+
+// Link returns the target of the RFC 8288 Link header with the relation rel, e.g. "next", resolved against the request URL.
+func (r Response) Link(rel string) (string, bool) {
+	values := r.Headers().Values(headerKeyLink)
+
+	for _, value := range values {
+		for _, link := range splitLinks(value) {
+			target, params, _ := strings.Cut(link, ";")
+			target = strings.TrimSpace(target)
+
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") || !hasRel(params, rel) {
+				continue
+			}
+
+			return r.resolveLink(target[1 : len(target)-1]), true
+		}
+	}
+
+	return "", false
+}
+
+// splitLinks splits a Link header value on the commas separating links, not those inside a target or a quoted param.
+func splitLinks(value string) []string {
+	var links []string
+	var inTarget, inQuotes bool
+
+	start := 0
+
+	for i, c := range value {
+		switch {
+		case c == '<' && !inQuotes:
+			inTarget = true
+		case c == '>' && !inQuotes:
+			inTarget = false
+		case c == '"' && !inTarget:
+			inQuotes = !inQuotes
+		case c == ',' && !inTarget && !inQuotes:
+			links = append(links, value[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(links, value[start:])
+}
+
+func hasRel(params, rel string) bool {
+	for _, param := range strings.Split(params, ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+
+		if !strings.EqualFold(strings.TrimSpace(key), "rel") {
+			continue
+		}
+
+		for _, candidate := range strings.Fields(strings.Trim(strings.TrimSpace(value), `"`)) {
+			if strings.EqualFold(candidate, rel) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (r Response) resolveLink(target string) string {
+	base, err := url.Parse(r.Request.URI.String())
+
+	if err != nil {
+		return target
+	}
+
+	ref, err := url.Parse(target)
+
+	if err != nil {
+		return target
+	}
+
+	return base.ResolveReference(ref).String()
+}
+
+// Paginate sends the request and follows the rel="next" Link of every page, calling fn with each of them until there is
+// no next link, fn returns false or an error. Pages are sent with a clone of the request, headers, authentication and
+// options included, the next link replacing the path and query. A next link to another host fails rather than sending
+// the credentials there.
+func (r *Request) Paginate(fn func(resp Response) (bool, error)) error {
+	page := r
+	visited := map[string]bool{}
+
+	for {
+		response, err := page.Send()
+
+		if err != nil {
+			return err
+		}
+
+		more, err := fn(response)
+
+		if err != nil || !more {
+			return err
+		}
+
+		next, ok := response.Link("next")
+
+		if !ok || visited[next] {
+			return nil
+		}
+
+		visited[next] = true
+
+		if page, err = r.nextPage(response, next); err != nil {
+			return err
+		}
+	}
+}
+
+func (r *Request) nextPage(response Response, next string) (*Request, error) {
+	nextURL, err := url.Parse(next)
+
+	if err != nil {
+		return nil, fmt.Errorf("parse next page link: %w", err)
+	}
+
+	if current, err := url.Parse(response.Request.URI.String()); err == nil && !strings.EqualFold(current.Host, nextURL.Host) {
+		return nil, fmt.Errorf("next page %s is on another host than %s", next, current.Host)
+	}
+
+	page := r.Clone()
+	page.path, page.baseUrl, page.pathParams, page.Query, page.rawQuery = next, "", nil, nil, ""
+
+	return page, nil
+}
@@ -0,0 +1,84 @@
+package room
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestRequest_Paginate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(headerKeyAuthorization) != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+
+		if page < 3 {
+			w.Header().Add(headerKeyLink, fmt.Sprintf(`</items?page=%d&per_page=2>; rel="next", </items?page=3>; rel="last"`, page+1))
+		}
+
+		_, _ = w.Write([]byte(strconv.Itoa(page)))
+	}))
+	defer server.Close()
+
+	var pages []string
+
+	err := NewRequest(server.URL+"/items", WithBearerToken("token"), WithQuery(NewMapQuery().Add("page", "1"))).
+		Paginate(func(resp Response) (bool, error) {
+			if resp.StatusCode != http.StatusOK {
+				return false, fmt.Errorf("unexpected status %d", resp.StatusCode)
+			}
+
+			pages = append(pages, string(resp.Data))
+
+			return true, nil
+		})
+
+	if err != nil || fmt.Sprint(pages) != "[1 2 3]" {
+		t.Errorf("Paginate() returned (%v, %v), expected [1 2 3]", pages, err)
+	}
+
+	pages = nil
+
+	_ = NewRequest(server.URL+"/items?page=1", WithBearerToken("token")).Paginate(func(resp Response) (bool, error) {
+		pages = append(pages, string(resp.Data))
+
+		return len(pages) < 2, nil
+	})
+
+	if fmt.Sprint(pages) != "[1 2]" {
+		t.Errorf("Paginate() fetched %v, expected to stop after [1 2]", pages)
+	}
+}
+
+func TestRequest_PaginateToAnotherHost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerKeyLink, `<http://elsewhere.example/items?page=2>; rel="next"`)
+	}))
+	defer server.Close()
+
+	err := NewRequest(server.URL, WithBearerToken("token")).Paginate(func(Response) (bool, error) { return true, nil })
+	if err == nil {
+		t.Error("Paginate() followed a next link to another host")
+	}
+}
+
+func TestResponse_Link(t *testing.T) {
+	response := newTestResponse("", "")
+	response.Header.Add(headerKeyLink, `<https://api.example.com/a,b>; rel="prev first"; title="x, y", <https://api.example.com/c>; rel=next`)
+
+	if link, ok := response.Link("first"); !ok || link != "https://api.example.com/a,b" {
+		t.Errorf("Link(first) returned (%s, %t), expected https://api.example.com/a,b", link, ok)
+	}
+	if link, ok := response.Link("next"); !ok || link != "https://api.example.com/c" {
+		t.Errorf("Link(next) returned (%s, %t), expected https://api.example.com/c", link, ok)
+	}
+	if _, ok := response.Link("last"); ok {
+		t.Error("Link(last) found a relation the header does not have")
+	}
+}
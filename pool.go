@@ -0,0 +1,94 @@
+package room
+
+import (
+	"net/http"
+	"sync"
+)
+
+// PoolConfig sizes the keep-alive connection pool. A zero field keeps the value of the transport, for the default one:
+//   - MaxIdleConns 100 idle connections across all hosts
+//   - MaxIdleConnsPerHost http.DefaultMaxIdleConnsPerHost, 2, which throttles reuse under concurrency to a single host
+//   - MaxConnsPerHost unlimited
+type PoolConfig struct {
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+}
+
+// WithConnectionPool sends the request with a transport sized by cfg, which every request with the same cfg and client
+// shares along with its connections. Along with other transport options, such as WithTLSConfig, cfg sizes the transport
+// shared by the requests with the same options, see WithDialContext for the exception.
+// To size the pool of the shared client itself, use ConfigureTransport(cfg.Configure).
+func WithConnectionPool(cfg PoolConfig) OptionRequest {
+	return func(request *Request) {
+		request.pool = &cfg
+		request.derivedClient = nil
+	}
+}
+
+type poolKey struct {
+	base *http.Transport
+	cfg  PoolConfig
+	// options chains the keys of the transport options applied before cfg, see Request.addTransportOption.
+	options any
+}
+
+var (
+	poolsMu sync.Mutex
+	pools   = map[poolKey]*http.Transport{}
+)
+
+// pooledTransport returns the clone of base sized by cfg, created on first use and shared afterwards.
+func pooledTransport(base *http.Transport, cfg PoolConfig) *http.Transport {
+	return sharedTransport(poolKey{base: base, cfg: cfg}, nil)
+}
+
+// sharedTransport returns the clone of key.base configured by opts then sized by key.cfg, created on first use
+// and shared afterwards by the requests with the same key.
+func sharedTransport(key poolKey, opts []func(transport *http.Transport)) *http.Transport {
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+
+	if transport, ok := pools[key]; ok {
+		return transport
+	}
+
+	transport := key.base.Clone()
+
+	for _, fn := range opts {
+		fn(transport)
+	}
+
+	key.cfg.Configure(transport)
+	pools[key] = transport
+
+	return transport
+}
+
+// closePools forgets the pools cloned from base, which ConfigureTransport replaced, and closes their idle connections.
+func closePools(base *http.Transport) {
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+
+	for key, transport := range pools {
+		if key.base == base {
+			transport.CloseIdleConnections()
+			delete(pools, key)
+		}
+	}
+}
+
+// Configure applies the pool limits to transport.
+func (cfg PoolConfig) Configure(transport *http.Transport) {
+	if cfg.MaxIdleConns > 0 {
+		transport.MaxIdleConns = cfg.MaxIdleConns
+	}
+
+	if cfg.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	}
+
+	if cfg.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = cfg.MaxConnsPerHost
+	}
+}
@@ -0,0 +1,60 @@
+package room
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequest_SendWithConnectionPool(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	request := NewRequest(server.URL, WithConnectionPool(PoolConfig{MaxIdleConnsPerHost: 16, MaxConnsPerHost: 32}))
+
+	for i := 0; i < 2; i++ {
+		response, err := request.Send()
+		if err != nil {
+			t.Fatalf("Send() returned unexpected error: %v", err)
+		}
+		if i > 0 && !response.Timings().ReusedConn {
+			t.Error("Send() did not reuse the pooled connection")
+		}
+	}
+
+	client, _ := request.httpClient()
+	transport := client.Transport.(*http.Transport)
+
+	if transport.MaxIdleConnsPerHost != 16 || transport.MaxConnsPerHost != 32 || transport.MaxIdleConns != 100 {
+		t.Errorf("WithConnectionPool() configured %d, %d and %d, expected 16, 32 and the default 100",
+			transport.MaxIdleConnsPerHost, transport.MaxConnsPerHost, transport.MaxIdleConns)
+	}
+	if DefaultClient().Transport.(*http.Transport).MaxConnsPerHost == 32 {
+		t.Error("WithConnectionPool() modified the shared transport")
+	}
+}
+
+func TestRequest_SendSharesConnectionPool(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	cfg := PoolConfig{MaxIdleConnsPerHost: 8}
+
+	for i := 0; i < 2; i++ {
+		response, err := NewRequest(server.URL, WithConnectionPool(cfg)).Send()
+		if err != nil {
+			t.Fatalf("Send() returned unexpected error: %v", err)
+		}
+		if i > 0 && !response.Timings().ReusedConn {
+			t.Error("Send() of a second request did not reuse the connection of the first one")
+		}
+	}
+
+	first, _ := NewRequest(server.URL, WithConnectionPool(cfg)).httpClient()
+	other, _ := NewRequest(server.URL, WithConnectionPool(PoolConfig{MaxIdleConnsPerHost: 4})).httpClient()
+	tls, _ := NewRequest(server.URL, WithConnectionPool(cfg), WithInsecureSkipVerify()).httpClient()
+
+	if first.Transport == other.Transport || first.Transport == tls.Transport {
+		t.Error("WithConnectionPool() shared a transport across different transport settings")
+	}
+}
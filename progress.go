@@ -0,0 +1,90 @@
+package room
+
+import (
+	"io"
+	"strconv"
+)
+
+// progressInterval is how many bytes are transferred between two progress callbacks.
+const progressInterval = 32 * 1024
+
+// ProgressFunc receives the bytes transferred so far and the total, -1 when the total is unknown.
+type ProgressFunc func(transferred, total int64)
+
+// WithUploadProgress reports the request body as the transport sends it, every 32KiB and once more when it is fully sent.
+func WithUploadProgress(fn ProgressFunc) OptionRequest {
+	return func(request *Request) {
+		request.uploadProgress = fn
+	}
+}
+
+type progressReader struct {
+	reader      io.Reader
+	fn          ProgressFunc
+	total       int64
+	transferred int64
+	reported    int64
+	done        bool
+}
+
+func newProgressReader(reader io.Reader, total int64, fn ProgressFunc) *progressReader {
+	return &progressReader{reader: reader, fn: fn, total: total, reported: -1}
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.reader.Read(b)
+
+	p.transferred += int64(n)
+
+	if err == io.EOF {
+		p.finish()
+	} else if p.transferred-max(p.reported, 0) >= progressInterval {
+		p.report()
+	}
+
+	return n, err
+}
+
+func (p *progressReader) report() {
+	p.reported = p.transferred
+	p.fn(p.transferred, p.total)
+}
+
+// finish delivers the final callback exactly once.
+func (p *progressReader) finish() {
+	if p.done {
+		return
+	}
+
+	p.done = true
+	p.report()
+}
+
+// OnProgress reports the body as it is read by Save, Bytes or the decode helpers, total being the Content-Length or -1.
+// Progress is only observable with WithStream, a buffered body reports its full size at once.
+func (r Response) OnProgress(fn ProgressFunc) Response {
+	total := int64(-1)
+
+	if length, err := strconv.ParseInt(r.Header.Get("Content-Length"), 10, 64); err == nil {
+		total = length
+	}
+
+	if r.body == nil {
+		fn(int64(len(r.Data)), int64(len(r.Data)))
+		return r
+	}
+
+	r.body.mu.Lock()
+	defer r.body.mu.Unlock()
+
+	if !r.body.buffered && !r.body.taken {
+		r.body.reader = readCloser{newProgressReader(r.body.reader, total, fn), r.body.reader}
+	}
+
+	return r
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,86 @@
+package room
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRequest_SendWithUploadProgress(t *testing.T) {
+	content := strings.Repeat("x", 100*1024)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength != int64(len(content)) {
+			t.Errorf("server received Content-Length %d, expected %d", r.ContentLength, len(content))
+		}
+		_, _ = io.Copy(io.Discard, r.Body)
+	}))
+	defer server.Close()
+
+	var calls [][2]int64
+	_, err := NewRequest(server.URL,
+		WithMethod(POST),
+		WithBody(ReaderBody(strings.NewReader(content), "text/plain")),
+		WithUploadProgress(func(sent, total int64) { calls = append(calls, [2]int64{sent, total}) }),
+	).Send()
+	if err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+
+	if len(calls) < 2 {
+		t.Fatalf("upload progress was reported %d times, expected intermediate and final calls", len(calls))
+	}
+	last := calls[len(calls)-1]
+	if last[0] != int64(len(content)) || last[1] != int64(len(content)) {
+		t.Errorf("final upload progress was %v, expected [%d %d]", last, len(content), len(content))
+	}
+}
+
+func TestRequest_SendWithUploadProgressUnknownLength(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+	}))
+	defer server.Close()
+
+	var total, sent int64
+	_, _ = NewRequest(server.URL,
+		WithMethod(POST),
+		WithBody(ReaderBody(io.MultiReader(strings.NewReader("abc")), "text/plain")),
+		WithUploadProgress(func(s, t int64) { sent, total = s, t }),
+	).Send()
+
+	if sent != 3 || total != -1 {
+		t.Errorf("final upload progress was (%d, %d), expected (3, -1)", sent, total)
+	}
+}
+
+func TestResponse_OnProgress(t *testing.T) {
+	content := strings.Repeat("y", 80*1024)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		_, _ = w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	response, err := NewRequest(server.URL, WithStream()).Send()
+	if err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+
+	var calls [][2]int64
+	response = response.OnProgress(func(read, total int64) { calls = append(calls, [2]int64{read, total}) })
+
+	if _, err = response.Save(io.Discard); err != nil {
+		t.Fatalf("Response Save() returned unexpected error: %v", err)
+	}
+
+	if len(calls) < 2 {
+		t.Fatalf("download progress was reported %d times, expected intermediate and final calls", len(calls))
+	}
+	last := calls[len(calls)-1]
+	if last[0] != int64(len(content)) || last[1] != int64(len(content)) {
+		t.Errorf("final download progress was %v, expected [%d %d]", last, len(content), len(content))
+	}
+}
@@ -0,0 +1,32 @@
+package room
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequest_SendWithProxy(t *testing.T) {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.String() + " " + r.Header.Get("Proxy-Authorization")))
+	}))
+	defer proxy.Close()
+
+	proxyURL := strings.Replace(proxy.URL, "http://", "http://user:secret@", 1)
+
+	response, err := NewRequest("http://api.example.invalid/users", WithProxy(proxyURL)).Send()
+	expected := "http://api.example.invalid/users Basic " + base64.StdEncoding.EncodeToString([]byte("user:secret"))
+	if err != nil || string(response.Data) != expected {
+		t.Errorf("WithProxy() Send() returned (%s, %v), expected %s", response.Data, err, expected)
+	}
+
+	if _, err = NewRequest("http://api.example.invalid/users", WithProxy("ftp://proxy.example.invalid")).Send(); err == nil {
+		t.Error("WithProxy() did not reject an unsupported proxy scheme")
+	}
+
+	if DefaultClient().Transport.(*http.Transport).Proxy == nil {
+		t.Error("DefaultClient() does not honor the proxy environment variables")
+	}
+}
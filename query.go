@@ -1,9 +1,12 @@
 package room
 
 import (
+	"fmt"
 	"github.com/WEG-Technology/room/store"
 	"github.com/google/go-querystring/query"
 	"net/url"
+	"reflect"
+	"strconv"
 )
 
 type Query struct {
@@ -27,11 +30,31 @@ type IMapQuery struct {
 	v store.IMap
 }
 
+// NewMapQuery builds an IMapQuery to which repeated params can be added.
+func NewMapQuery(data ...map[string]any) IMapQuery {
+	return IMapQuery{store.NewMapStore(data...)}
+}
+
+// Add appends value to key, keeping the values already added, so "tag=a&tag=b" can be built.
+func (q IMapQuery) Add(key, value string) IMapQuery {
+	existing, ok := q.v.GetItem(key)
+
+	if !ok {
+		q.v.Add(key, value)
+		return q
+	}
+
+	q.v.Add(key, append(append([]string{}, queryValues(existing)...), value))
+
+	return q
+}
+
+// String encodes slice values as repeated params and any other non-string value with fmt.
 func (q IMapQuery) String() string {
 	v := url.Values{}
 
 	q.v.Each(func(key string, value any) {
-		v.Add(key, value.(string))
+		v[key] = append(v[key], queryValues(value)...)
 	})
 
 	vEncoded := v.Encode()
@@ -39,6 +62,25 @@ func (q IMapQuery) String() string {
 	return vEncoded
 }
 
+func queryValues(value any) []string {
+	switch value := value.(type) {
+	case string:
+		return []string{value}
+	case []string:
+		return value
+	case []any:
+		values := make([]string, len(value))
+
+		for i, item := range value {
+			values[i] = fmt.Sprint(item)
+		}
+
+		return values
+	default:
+		return []string{fmt.Sprint(value)}
+	}
+}
+
 type IUrlQuery struct {
 	v any
 }
@@ -52,3 +94,54 @@ func (q IUrlQuery) String() string {
 
 	return urlValues.Encode()
 }
+
+// QueryFromStruct encodes v with its `url` struct tags, fields tagged "-" are skipped.
+// Nested structs are encoded as "parent[child]" and slices as repeated params.
+func QueryFromStruct(v any) IQuery {
+	return IUrlQuery{v}
+}
+
+// QueryFromMap encodes m the same way QueryFromStruct does: nested maps as "parent[child]"
+// and slices as repeated params, or as "parent[index][child]" when they hold maps.
+func QueryFromMap(m map[string]any) IQuery {
+	q := NewMapQuery()
+
+	for key, value := range m {
+		flattenQuery(q, key, value)
+	}
+
+	return q
+}
+
+func flattenQuery(q IMapQuery, key string, value any) {
+	if value == nil {
+		return
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		for childKey, childValue := range value {
+			flattenQuery(q, key+"["+childKey+"]", childValue)
+		}
+		return
+	case string:
+		q.Add(key, value)
+		return
+	}
+
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		for i := 0; i < rv.Len(); i++ {
+			item := rv.Index(i).Interface()
+
+			if _, ok := item.(map[string]any); ok {
+				flattenQuery(q, key+"["+strconv.Itoa(i)+"]", item)
+			} else {
+				flattenQuery(q, key, item)
+			}
+		}
+
+		return
+	}
+
+	q.Add(key, fmt.Sprint(value))
+}
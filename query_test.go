@@ -63,3 +63,49 @@ func TestIUrlQuery_String(t *testing.T) {
 		t.Errorf("IUrlQuery String() returned %s for invalid URL values, expected an empty string", result)
 	}
 }
+
+func TestIMapQuery_Add(t *testing.T) {
+	query := NewMapQuery().Add("tag", "a").Add("tag", "b&c").Add("q", "hello world")
+	expected := "q=hello+world&tag=a&tag=b%26c"
+	if result := query.String(); result != expected {
+		t.Errorf("IMapQuery Add() produced %s, expected %s", result, expected)
+	}
+}
+
+func TestIMapQuery_StringWithSlices(t *testing.T) {
+	query := NewMapQuery(map[string]any{"ids": []string{"1", "2"}, "page": 3, "mixed": []any{"x", 4}})
+	expected := "ids=1&ids=2&mixed=x&mixed=4&page=3"
+	if result := query.String(); result != expected {
+		t.Errorf("IMapQuery String() returned %s, expected %s", result, expected)
+	}
+}
+
+func TestQueryFromMap(t *testing.T) {
+	query := QueryFromMap(map[string]any{
+		"tags":   []string{"a", "b"},
+		"ids":    []int{1, 2},
+		"filter": map[string]any{"status": "active", "age": 30},
+		"items":  []any{map[string]any{"id": 1}},
+		"empty":  nil,
+	})
+	expected := "filter%5Bage%5D=30&filter%5Bstatus%5D=active&ids=1&ids=2&items%5B0%5D%5Bid%5D=1&tags=a&tags=b"
+	if result := query.String(); result != expected {
+		t.Errorf("QueryFromMap() returned %s, expected %s", result, expected)
+	}
+}
+
+func TestQueryFromStruct(t *testing.T) {
+	type Filter struct {
+		Status string `url:"status"`
+	}
+	query := QueryFromStruct(struct {
+		Tags   []string `url:"tag"`
+		Secret string   `url:"-"`
+		Filter Filter   `url:"filter"`
+	}{[]string{"a", "b"}, "hidden", Filter{"active"}})
+
+	expected := "filter%5Bstatus%5D=active&tag=a&tag=b"
+	if result := query.String(); result != expected {
+		t.Errorf("QueryFromStruct() returned %s, expected %s", result, expected)
+	}
+}
@@ -0,0 +1,132 @@
+package room
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Limit is a rate of events per second.
+type Limit float64
+
+// Inf disables rate limiting.
+const Inf = Limit(math.MaxFloat64)
+
+// Every converts the minimum interval between events to a Limit.
+func Every(interval time.Duration) Limit {
+	if interval <= 0 {
+		return Inf
+	}
+
+	return Limit(1 / interval.Seconds())
+}
+
+// RateLimiter is a token bucket holding up to burst tokens, refilled at limit tokens per second.
+// A limit of zero or less only lets the initial burst through.
+type RateLimiter struct {
+	mu     sync.Mutex
+	limit  Limit
+	burst  int
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// NewRateLimiter creates a full bucket, a burst lower than one is raised to one.
+func NewRateLimiter(limit Limit, burst int) *RateLimiter {
+	burst = max(burst, 1)
+
+	return &RateLimiter{limit: limit, burst: burst, tokens: float64(burst), now: time.Now}
+}
+
+// Wait blocks until a token is available or ctx is done, a cancelled wait gives its token back.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	delay, ok := l.reserve()
+
+	if ok && delay == 0 {
+		return nil
+	}
+
+	var expired <-chan time.Time
+
+	if ok {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		expired = timer.C
+	}
+
+	select {
+	case <-expired:
+		return nil
+	case <-ctx.Done():
+		l.release()
+
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token, possibly going into debt, and returns how long to wait for it to be refilled.
+// ok is false when it never will be.
+func (l *RateLimiter) reserve() (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limit == Inf {
+		return 0, true
+	}
+
+	now := l.now()
+
+	if l.limit > 0 && !l.last.IsZero() {
+		l.tokens = math.Min(float64(l.burst), l.tokens+now.Sub(l.last).Seconds()*float64(l.limit))
+	}
+
+	l.last = now
+	l.tokens--
+
+	if l.tokens >= 0 {
+		return 0, true
+	}
+
+	if l.limit <= 0 {
+		return 0, false
+	}
+
+	return time.Duration(-l.tokens / float64(l.limit) * float64(time.Second)), true
+}
+
+func (l *RateLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens = math.Min(float64(l.burst), l.tokens+1)
+}
+
+// WithRateLimit waits for a token before every attempt, see RateLimitMiddleware.
+// Requests built with the same option value share its limiters, as the requests of a Client using WithRateLimitClient do.
+func WithRateLimit(limit Limit, burst int) OptionRequest {
+	return WithMiddleware(RateLimitMiddleware(limit, burst))
+}
+
+// RateLimitMiddleware keeps a RateLimiter per host and waits for a token of the host of the request before sending it.
+// The wait respects the request context, Send fails with its error once it is done.
+func RateLimitMiddleware(limit Limit, burst int) Middleware {
+	limiters := newHostMap(func() *RateLimiter {
+		return NewRateLimiter(limit, burst)
+	})
+
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if err := limiters.get(req.URL.Host).Wait(req.Context()); err != nil {
+				closeReaders(req.Body)
+
+				return nil, err
+			}
+
+			return next(req)
+		}
+	}
+}
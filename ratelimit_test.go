@@ -0,0 +1,75 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Reserve(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(Every(100*time.Millisecond), 2)
+	limiter.now = func() time.Time { return now }
+
+	var delays []time.Duration
+	for i := 0; i < 4; i++ {
+		delay, _ := limiter.reserve()
+		delays = append(delays, delay)
+	}
+
+	expected := []time.Duration{0, 0, 100 * time.Millisecond, 200 * time.Millisecond}
+	for i := range expected {
+		if delays[i] != expected[i] {
+			t.Errorf("reserve() returned delays %v, expected %v", delays, expected)
+			break
+		}
+	}
+
+	now = now.Add(time.Second)
+	if delay, _ := limiter.reserve(); delay != 0 {
+		t.Errorf("reserve() returned %v after the bucket refilled, expected 0", delay)
+	}
+}
+
+func TestRateLimiter_WaitCanceled(t *testing.T) {
+	limiter := NewRateLimiter(Every(time.Hour), 1)
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() returned %v for the burst token, expected nil", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() returned %v, expected context.DeadlineExceeded", err)
+	}
+	if limiter.tokens < 0 {
+		t.Errorf("Wait() left %v tokens after a cancelled wait, expected the token back", limiter.tokens)
+	}
+}
+
+func TestClient_SendWithRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithRateLimitClient(Every(time.Hour), 1))
+
+	if _, err := client.Get("/first").Send(); err != nil {
+		t.Fatalf("Send() returned %v for the burst token, expected nil", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.Get("/second").SendWithContext(ctx); !IsTimeout(err) {
+		t.Errorf("Send() returned %v, expected the requests of the client to share the limiter of the host", err)
+	}
+
+	if _, err := NewRequest(server.URL, WithRateLimit(Every(time.Hour), 1)).Send(); err != nil {
+		t.Errorf("Send() returned %v, expected a limiter of its own for a request outside the client", err)
+	}
+}
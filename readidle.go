@@ -0,0 +1,71 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+var ErrReadIdleTimeout = errors.New("response body read idle timeout")
+
+// WithReadIdleTimeout aborts reading the response body once a read has waited d without receiving any data,
+// the read error wrapping ErrReadIdleTimeout. The request context is cancelled with it, closing the connection of
+// an upstream that keeps it open but stopped sending. Unlike WithTimeout it does not bound the whole request,
+// and with WithStream the time spent between two reads does not count.
+func WithReadIdleTimeout(d time.Duration) OptionRequest {
+	return func(request *Request) {
+		request.readIdleTimeout = d
+	}
+}
+
+// attemptContext derives the context of one attempt, which a stalled read cancels with WithReadIdleTimeout.
+func (r *Request) attemptContext(ctx context.Context) (context.Context, context.CancelCauseFunc) {
+	if r.readIdleTimeout <= 0 {
+		return ctx, func(error) {}
+	}
+
+	return context.WithCancelCause(ctx)
+}
+
+// idleTimeoutBody runs a timer for the duration of each read, the context being cancelled when it fires.
+type idleTimeoutBody struct {
+	body     io.ReadCloser
+	timeout  time.Duration
+	timer    *time.Timer
+	cancel   context.CancelCauseFunc
+	timedOut atomic.Bool
+}
+
+func newIdleTimeoutBody(body io.ReadCloser, timeout time.Duration, cancel context.CancelCauseFunc) *idleTimeoutBody {
+	b := &idleTimeoutBody{body: body, timeout: timeout, cancel: cancel}
+
+	b.timer = time.AfterFunc(timeout, func() {
+		b.timedOut.Store(true)
+		cancel(ErrReadIdleTimeout)
+	})
+	b.timer.Stop()
+
+	return b
+}
+
+func (b *idleTimeoutBody) Read(p []byte) (int, error) {
+	b.timer.Reset(b.timeout)
+	n, err := b.body.Read(p)
+	b.timer.Stop()
+
+	if b.timedOut.Load() {
+		return n, ErrReadIdleTimeout
+	}
+
+	return n, err
+}
+
+func (b *idleTimeoutBody) Close() error {
+	b.timer.Stop()
+	err := b.body.Close()
+	b.cancel(nil)
+
+	return err
+}
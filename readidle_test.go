@@ -0,0 +1,62 @@
+package room
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequest_SendWithReadIdleTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < 3; i++ {
+			_, _ = w.Write([]byte("chunk"))
+			w.(http.Flusher).Flush()
+			time.Sleep(20 * time.Millisecond)
+		}
+
+		if r.URL.Path == "/stall" {
+			<-r.Context().Done()
+		}
+	}))
+	defer server.Close()
+
+	response, err := NewRequest(server.URL, WithReadIdleTimeout(200*time.Millisecond)).Send()
+	if err != nil || string(response.Data) != "chunkchunkchunk" {
+		t.Errorf("Send() returned (%s, %v), expected a body arriving in time to be read", response.Data, err)
+	}
+
+	start := time.Now()
+	_, err = NewRequest(server.URL+"/stall", WithReadIdleTimeout(100*time.Millisecond), WithTimeout(5*time.Second)).Send()
+	if !errors.Is(err, ErrReadIdleTimeout) || time.Since(start) > 2*time.Second {
+		t.Errorf("Send() returned %v after %s, expected ErrReadIdleTimeout once the body stalled", err, time.Since(start))
+	}
+}
+
+func TestRequest_SendWithReadIdleTimeoutStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("body"))
+	}))
+	defer server.Close()
+
+	response, err := NewRequest(server.URL, WithReadIdleTimeout(50*time.Millisecond), WithStream()).Send()
+	if err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+
+	// The caller pausing before reading is not a stall of the upstream.
+	time.Sleep(100 * time.Millisecond)
+
+	reader, err := response.bodyReader()
+	if err != nil {
+		t.Fatalf("bodyReader() returned unexpected error: %v", err)
+	}
+
+	if data, err := io.ReadAll(reader); err != nil || string(data) != "body" {
+		t.Errorf("ReadAll() returned (%s, %v), expected body", data, err)
+	}
+
+	_ = response.Close()
+}
@@ -0,0 +1,137 @@
+package room
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RedactionConfig masks secrets in what the logger, ToCurl and ToHAR export, values being replaced by ***.
+// The Authorization, Proxy-Authorization, Cookie and Set-Cookie headers are always redacted.
+type RedactionConfig struct {
+	// Headers are additional header names to redact, matched case-insensitively.
+	Headers []string
+	// Fields are paths of JSON body fields to redact in the JSONPath syntax, "*" matching every key or element,
+	// e.g. "password" or "users.*.token".
+	Fields []string
+}
+
+type redactionKey struct{}
+
+// WithRedaction masks cfg in the dumps of a verbose StdLogger, in ToCurl and in ToHAR. The request sent is unchanged.
+// Without it the logger and ToHAR still redact the sensitive headers.
+func WithRedaction(cfg RedactionConfig) OptionRequest {
+	cfg.Headers, cfg.Fields = slices.Clone(cfg.Headers), slices.Clone(cfg.Fields)
+
+	return func(request *Request) {
+		request.redaction = &cfg
+	}
+}
+
+// defaultRedaction masks the sensitive headers of a request sent without WithRedaction.
+var defaultRedaction = &RedactionConfig{}
+
+// redactionFrom returns the config of the request that ctx was sent with, defaultRedaction when it has none.
+func redactionFrom(ctx context.Context) *RedactionConfig {
+	if cfg, ok := ctx.Value(redactionKey{}).(*RedactionConfig); ok && cfg != nil {
+		return cfg
+	}
+
+	return defaultRedaction
+}
+
+func (c *RedactionConfig) isSensitive(name string) bool {
+	if c == nil {
+		return false
+	}
+
+	return isSensitiveHeader(name) || slices.ContainsFunc(c.Headers, func(header string) bool { return strings.EqualFold(header, name) })
+}
+
+// header returns a copy of header with the sensitive values redacted, header itself when there is nothing to redact.
+func (c *RedactionConfig) header(header http.Header) http.Header {
+	if c == nil {
+		return header
+	}
+
+	redacted := header.Clone()
+
+	for name, values := range redacted {
+		if c.isSensitive(name) {
+			for i := range values {
+				values[i] = redactedValue
+			}
+		}
+	}
+
+	return redacted
+}
+
+// body redacts the fields of a JSON body, any other body is returned as it is.
+func (c *RedactionConfig) body(data []byte) []byte {
+	if c == nil || len(c.Fields) == 0 || !json.Valid(data) {
+		return data
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+
+	if decoder.Decode(&value) != nil {
+		return data
+	}
+
+	redacted := false
+
+	for _, field := range c.Fields {
+		if segments, err := parseJSONPath(field); err == nil && len(segments) > 0 {
+			value = redactJSON(value, segments, &redacted)
+		}
+	}
+
+	if !redacted {
+		return data
+	}
+
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+
+	if encoder.Encode(value) != nil {
+		return data
+	}
+
+	return bytes.TrimSuffix(out.Bytes(), []byte("\n"))
+}
+
+func redactJSON(value any, segments []string, redacted *bool) any {
+	if len(segments) == 0 {
+		*redacted = true
+
+		return redactedValue
+	}
+
+	segment, rest := segments[0], segments[1:]
+
+	switch node := value.(type) {
+	case map[string]any:
+		for key, child := range node {
+			if segment == "*" || segment == key {
+				node[key] = redactJSON(child, rest, redacted)
+			}
+		}
+	case []any:
+		for i, child := range node {
+			if segment == "*" || segment == strconv.Itoa(i) {
+				node[i] = redactJSON(child, rest, redacted)
+			}
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,82 @@
+package room
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedactionConfig_Body(t *testing.T) {
+	cfg := &RedactionConfig{Fields: []string{"password", "users.*.token", "missing.field"}}
+
+	redacted := string(cfg.body([]byte(`{"password":"p","users":[{"token":"t1","name":"a<b"},{"token":"t2"}],"count":10000000000000001}`)))
+	expected := `{"count":10000000000000001,"password":"***","users":[{"name":"a<b","token":"***"},{"token":"***"}]}`
+	if redacted != expected {
+		t.Errorf("body() returned %s, expected %s", redacted, expected)
+	}
+
+	for _, body := range []string{`{"name":"a"}`, "password=p", ""} {
+		if redacted := string(cfg.body([]byte(body))); redacted != body {
+			t.Errorf("body(%s) returned %s, expected the body unchanged", body, redacted)
+		}
+	}
+
+	header := cfg.header(http.Header{"Authorization": {"Bearer secret"}, "X-Api-Key": {"key"}})
+	if header.Get("Authorization") != redactedValue || header.Get("X-Api-Key") != "key" {
+		t.Errorf("header() returned %v, expected only the default headers redacted", header)
+	}
+}
+
+func TestRequest_SendWithRedaction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "key" || r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "cookie-secret"})
+		w.Header().Set(headerKeyContentType, headerValueApplicationJson)
+		_, _ = w.Write([]byte(`{"token":"response-secret","id":1}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	request := NewRequest(server.URL,
+		WithMethod(POST),
+		WithHeader(NewHeader().Add("X-Api-Key", "key")),
+		WithBearerToken("secret"),
+		WithBody(JSONBody(map[string]string{"password": "request-secret", "name": "room"})),
+		WithRedaction(RedactionConfig{Headers: []string{"x-api-key"}, Fields: []string{"password", "token"}}),
+		WithLogger(NewStdLogger(log.New(&out, "", 0), true)))
+
+	response, err := request.Send()
+	if err != nil || response.StatusCode != http.StatusOK || !strings.Contains(string(response.Data), "response-secret") {
+		t.Fatalf("Send() returned (%d %s, %v), expected the request and response unchanged", response.StatusCode, response.Data, err)
+	}
+
+	har, err := response.ToHAR()
+	if err != nil {
+		t.Fatalf("ToHAR() returned unexpected error: %v", err)
+	}
+
+	command, err := request.ToCurl()
+	if err != nil {
+		t.Fatalf("ToCurl() returned unexpected error: %v", err)
+	}
+
+	exports := map[string]string{"logger": out.String(), "ToHAR()": string(har), "ToCurl()": command}
+
+	for name, export := range exports {
+		for _, secret := range []string{"Bearer secret", ": key", `"key"`, "request-secret", "response-secret", "cookie-secret"} {
+			if strings.Contains(export, secret) {
+				t.Errorf("%s exported %q, expected %q redacted", name, export, secret)
+			}
+		}
+
+		if !strings.Contains(export, redactedValue) || !strings.Contains(export, "room") {
+			t.Errorf("%s exported %q, expected the redacted values masked and the others kept", name, export)
+		}
+	}
+}
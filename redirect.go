@@ -0,0 +1,36 @@
+package room
+
+import "net/http"
+
+// WithMaxRedirects follows at most n redirects and returns the last 3xx response once the limit is reached.
+func WithMaxRedirects(n int) OptionRequest {
+	return func(request *Request) {
+		request.checkRedirect = func(req *http.Request, via []*http.Request) error {
+			if len(via) > n {
+				return http.ErrUseLastResponse
+			}
+
+			return nil
+		}
+	}
+}
+
+// WithNoRedirect returns 3xx responses to the caller instead of following them.
+func WithNoRedirect() OptionRequest {
+	return WithMaxRedirects(0)
+}
+
+// chainCheckRedirect runs the request policy first and then the CheckRedirect of an injected client, if any.
+func chainCheckRedirect(policy, clientPolicy func(req *http.Request, via []*http.Request) error) func(req *http.Request, via []*http.Request) error {
+	if clientPolicy == nil {
+		return policy
+	}
+
+	return func(req *http.Request, via []*http.Request) error {
+		if err := policy(req, via); err != nil {
+			return err
+		}
+
+		return clientPolicy(req, via)
+	}
+}
@@ -0,0 +1,69 @@
+package room
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func newRedirectServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hops, _ := strconv.Atoi(r.URL.Query().Get("hops"))
+		if hops < 5 {
+			http.Redirect(w, r, "/?hops="+strconv.Itoa(hops+1), http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func TestRequest_SendWithNoRedirect(t *testing.T) {
+	server := newRedirectServer()
+	defer server.Close()
+
+	response, err := NewRequest(server.URL, WithNoRedirect()).Send()
+	if err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+	if response.StatusCode != http.StatusFound {
+		t.Errorf("Send() returned status %d, expected 302", response.StatusCode)
+	}
+	if response.Header.Get("Location") != "/?hops=1" {
+		t.Errorf("Send() returned Location %q, expected /?hops=1", response.Header.Get("Location"))
+	}
+}
+
+func TestRequest_SendWithMaxRedirects(t *testing.T) {
+	server := newRedirectServer()
+	defer server.Close()
+
+	response, _ := NewRequest(server.URL, WithMaxRedirects(2)).Send()
+	if response.StatusCode != http.StatusFound || response.Header.Get("Location") != "/?hops=3" {
+		t.Errorf("Send() stopped at status %d with Location %q, expected 302 to /?hops=3", response.StatusCode, response.Header.Get("Location"))
+	}
+
+	response, _ = NewRequest(server.URL, WithMaxRedirects(10)).Send()
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Send() returned status %d, expected 200", response.StatusCode)
+	}
+}
+
+func TestRequest_SendWithMaxRedirectsKeepsClientPolicy(t *testing.T) {
+	server := newRedirectServer()
+	defer server.Close()
+
+	errClientPolicy := errors.New("client policy")
+	client := &http.Client{CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return errClientPolicy
+	}}
+
+	_, err := NewRequest(server.URL, WithClient(client), WithMaxRedirects(2)).Send()
+	if !errors.Is(err, errClientPolicy) {
+		t.Errorf("Send() returned %v, expected the injected client's CheckRedirect error", err)
+	}
+	if client.CheckRedirect == nil {
+		t.Error("WithMaxRedirects() overwrote the injected client's CheckRedirect")
+	}
+}
@@ -1,7 +1,13 @@
 package room
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptrace"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -9,10 +15,15 @@ import (
 const (
 	headerKeyContentType         = "Content-Type"
 	headerKeyAccept              = "Accept"
+	headerKeyAcceptEncoding      = "Accept-Encoding"
+	headerKeyAuthorization       = "Authorization"
+	headerKeyUserAgent           = "User-Agent"
 	headerValueFormEncoded       = "application/x-www-form-urlencoded"
 	headerValueApplicationJson   = "application/json"
 	headerValueTextXML           = "text/xml"
+	headerValueApplicationXML    = "application/xml"
 	headerValueMultipartFormData = "multipart/form-data"
+	headerValueOctetStream       = "application/octet-stream"
 )
 
 type ISend interface {
@@ -20,14 +31,51 @@ type ISend interface {
 }
 
 type Request struct {
-	path           string
-	URI            URI
-	Method         HTTPMethod
-	Header         IHeader
-	Query          IQuery
-	BodyParser     IBodyParser
-	contextBuilder IContextBuilder
-	Cookies        []*http.Cookie
+	path             string
+	baseUrl          string
+	URI              URI
+	Method           HTTPMethod
+	Header           IHeader
+	Query            IQuery
+	BodyParser       IBodyParser
+	contextBuilder   IContextBuilder
+	Cookies          []*http.Cookie
+	client           *http.Client
+	retry            *retryPolicy
+	authorization    string
+	gzipBody         bool
+	rawResponse      bool
+	checkRedirect    func(req *http.Request, via []*http.Request) error
+	jar              http.CookieJar
+	middlewares      []Middleware
+	ctx              context.Context
+	pathParams       map[string]string
+	stream           bool
+	uploadProgress   ProgressFunc
+	userAgent        string
+	transportOpts    []func(transport *http.Transport)
+	transportKey     any
+	privateTransport bool
+	pool             *PoolConfig
+	idempotencyKey   string
+	idempotencyErr   error
+	ifMatch          string
+	ifNoneMatch      string
+	lastEventID      string
+	overrideClient   bool
+	derivedClient    *http.Client
+	derivedFrom      *http.Client
+	signers          []Signer
+	roundTripper     http.RoundTripper
+	accept           string
+	errorOnStatus    bool
+	maxResponseSize  int64
+	redaction        *RedactionConfig
+	readIdleTimeout  time.Duration
+	rawQuery         string
+	host             string
+	values           []contextValue
+	hostHeaders      hostHeaders
 }
 
 // NewRequest creates a new request
@@ -54,69 +102,318 @@ func NewRequest(path string, opts ...OptionRequest) *Request {
 }
 
 func (r *Request) Send() (Response, error) {
-	c := new(http.Client)
+	context := r.context()
 
-	req := r.request()
+	response, err := r.SendWithContext(context.Ctx)
 
-	response, err := c.Do(req)
+	if context.Cancel != nil {
+		if response.body != nil {
+			response.body.onClose = context.Cancel
+		} else {
+			context.Cancel()
+		}
+	}
+
+	return response, err
+}
+
+// SendWithContext sends the request with ctx as-is, WithContext, WithTimeout and the context builder are not applied.
+// Send delegates to it with the context derived from them.
+func (r *Request) SendWithContext(ctx context.Context) (Response, error) {
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := r.attemptContext(ctx)
+		req, err := r.request(attemptCtx)
+
+		if err != nil {
+			cancel(nil)
+
+			return NewErrorResponse(req, err)
+		}
+
+		trace := newTimingTrace()
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace.clientTrace()))
+
+		response, err := r.roundTrip(req)
+		timings := trace.done()
+
+		if err != nil {
+			cancel(nil)
+		} else if r.readIdleTimeout > 0 {
+			response.Body = newIdleTimeoutBody(response.Body, r.readIdleTimeout, cancel)
+		}
+
+		if !r.retry.shouldRetry(attempt, response, err) || ctx.Err() != nil {
+			if err != nil {
+				return NewErrorResponse(req, err)
+			}
+
+			responseDTO := newHTTPResponse(response, !r.rawResponse, r.stream, r.maxResponseSize)
+			responseDTO.startedAt, responseDTO.timings = trace.start, timings
+
+			if responseDTO.readErr != nil {
+				return responseDTO, newRequestError(req, responseDTO.readErr)
+			}
+
+			if r.errorOnStatus {
+				return responseDTO, responseDTO.Error()
+			}
+
+			return responseDTO, nil
+		}
+
+		discardBody(response)
+
+		if err = r.retry.wait(ctx, attempt, response); err != nil {
+			return NewErrorResponse(req, err)
+		}
+	}
+}
+
+// Build constructs the *http.Request that Send would send, with its headers, query, body, cookies and signatures,
+// without sending it. It carries the context of WithContext, or the background one, rather than the timeout of Send.
+// Middlewares and the client are not applied, they only act on a sent request. The body is read by whoever sends it.
+func (r *Request) Build() (*http.Request, error) {
+	ctx := r.ctx
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	req, err := r.request(ctx)
 
 	if err != nil {
-		return NewErrorResponse(req, err)
+		return nil, newRequestError(req, err)
 	}
 
-	return NewResponse(response, req), nil
+	return req, nil
 }
 
-func (r *Request) request() *http.Request {
-	var context Context
+// context derives the Send context. A caller supplied context is used as-is unless a ContextBuilder
+// such as WithTimeout also applies, in which case its timeout is layered on top of it.
+func (r *Request) context() Context {
+	if r.ctx != nil {
+		if builder, ok := r.contextBuilder.(ContextBuilder); ok {
+			return builder.build(r.ctx)
+		}
+
+		return Context{Ctx: r.ctx}
+	}
 
 	if r.contextBuilder != nil {
-		context = r.contextBuilder.Build()
-	} else {
-		context = NewContextBuilder(30 * time.Second).Build()
+		return r.contextBuilder.Build()
+	}
+
+	return NewContextBuilder(DefaultTimeout).Build()
+}
+
+func (r *Request) request(ctx context.Context) (*http.Request, error) {
+	if r.idempotencyErr != nil {
+		return nil, r.idempotencyErr
+	}
+
+	path, err := expandPath(r.path, r.pathParams)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if r.baseUrl != "" {
+		path = joinURL(r.baseUrl, path)
 	}
 
 	if r.Query != nil && r.Query.String() != "" {
-		r.URI = NewURI(r.path + "?" + r.Query.String())
-	} else {
-		r.URI = NewURI(r.path)
+		path = appendQuery(path, r.Query.String())
+	}
+
+	if r.rawQuery != "" {
+		path = appendQuery(path, strings.TrimPrefix(r.rawQuery, "?"))
 	}
 
-	req, _ := http.NewRequestWithContext(context.Ctx, r.Method.String(), r.URI.String(), r.BodyParser.Parse())
+	if r.URI, err = ParseURI(path); err != nil {
+		return nil, err
+	}
+
+	parsed, err := r.BodyParser.Parse()
+
+	if err != nil {
+		return nil, err
+	}
+
+	body := parsed
+	compress := r.gzipBody && !isEmptyBody(body)
+
+	if compress {
+		body = gzipReader(body)
+	}
+
+	var signedBody []byte
+
+	if len(r.signers) > 0 && !isEmptyBody(body) {
+		signedBody, err = io.ReadAll(body)
+		closeReaders(body, parsed)
+
+		if err != nil {
+			return nil, fmt.Errorf("read request body: %w", err)
+		}
+
+		body = bytes.NewReader(signedBody)
+	}
+
+	length := bodyLength(body)
+
+	if r.uploadProgress != nil && !isEmptyBody(body) {
+		body = newProgressReader(body, length, r.uploadProgress)
+	}
+
+	if r.redaction != nil {
+		ctx = context.WithValue(ctx, redactionKey{}, r.redaction)
+	}
+
+	for _, value := range r.values {
+		ctx = context.WithValue(ctx, value.key, value.value)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.Method.String(), r.URI.String(), body)
+
+	if err != nil {
+		// Closing both ends stops the goroutines feeding piped bodies such as gzip and multipart.
+		closeReaders(body, parsed)
+
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+
+	// GetBody lets the transport send the body again on a 307 or 308 redirect, and middlewares replay it after a 401.
+	// The response does not record such a body, it is streamed rather than held in memory.
+	if req.GetBody == nil && req.Body != nil && req.Body != http.NoBody {
+		req.GetBody = r.getBody(compress)
+		req = req.WithContext(context.WithValue(req.Context(), streamedBodyKey{}, true))
+
+		// Bodies read from a reader of the caller replay one source, the readers they return cannot be read concurrently.
+		if shared, ok := r.BodyParser.(sharedSourceBody); ok && shared.sharesSource() {
+			req = req.WithContext(context.WithValue(req.Context(), sharedBodyKey{}, true))
+		}
+	}
+
+	// An unknown length, a pipe for instance, is sent with chunked transfer encoding rather than as an empty body.
+	if length > 0 && req.ContentLength == 0 {
+		req.ContentLength = length
+	} else if length < 0 && req.Body != nil && req.Body != http.NoBody {
+		req.ContentLength = -1
+	}
 
 	if r.Header != nil {
-		r.Header.Properties().Each(func(k string, v any) {
-			req.Header.Add(k, v.(string))
+		asHeader(r.Header).Each(func(key, value string) {
+			req.Header.Add(key, value)
 		})
 	}
 
+	req = r.hostHeaders.inject(req)
+
+	// Go sends req.Host rather than a Host header, the connection still goes to the host of the URL.
+	if r.host != "" {
+		req.Host = r.host
+	} else if host := req.Header.Get("Host"); host != "" {
+		req.Host = host
+	}
+
 	if r.BodyParser.ContentType() != "" {
 		req.Header.Set("Content-Type", r.BodyParser.ContentType())
 	}
 
+	// WithUserAgent wins over a User-Agent passed with the headers, which wins over DefaultUserAgent.
+	if r.userAgent != "" {
+		req.Header.Set(headerKeyUserAgent, r.userAgent)
+	} else if req.Header.Get(headerKeyUserAgent) == "" {
+		req.Header.Set(headerKeyUserAgent, DefaultUserAgent)
+	}
+
+	if r.accept != "" {
+		req.Header.Set(headerKeyAccept, r.accept)
+	}
+
+	if compress {
+		req.Header.Set(headerKeyContentEncoding, encodingGzip)
+	}
+
+	// The transport only asks for gzip, and decodes it itself, when Accept-Encoding is not set.
+	if encodings := acceptEncoding(); encodings != "" && !r.rawResponse && req.Header.Get(headerKeyAcceptEncoding) == "" {
+		req.Header.Set(headerKeyAcceptEncoding, encodings)
+	}
+
+	if r.authorization != "" {
+		req.Header.Set(headerKeyAuthorization, r.authorization)
+	}
+
+	if r.idempotencyKey != "" {
+		req.Header.Set(headerKeyIdempotencyKey, r.idempotencyKey)
+	}
+
+	if r.ifMatch != "" {
+		req.Header.Set(headerKeyIfMatch, r.ifMatch)
+	}
+
+	if r.ifNoneMatch != "" {
+		req.Header.Set(headerKeyIfNoneMatch, r.ifNoneMatch)
+	}
+
+	if r.lastEventID != "" {
+		req.Header.Set(headerKeyLastEventID, r.lastEventID)
+	}
+
 	if r.Cookies != nil && len(r.Cookies) > 0 {
 		for _, cookie := range r.Cookies {
 			req.AddCookie(cookie)
 		}
 	}
 
-	return req
-}
+	for _, signer := range r.signers {
+		if err = signer.Sign(req, signedBody); err != nil {
+			closeReaders(req.Body)
 
-func (r *Request) SetBaseUrl(baseUrl string) *Request {
-	if strings.HasPrefix(r.path, "/") {
-		r.path = r.path[1:]
+			return nil, fmt.Errorf("sign request: %w", err)
+		}
 	}
 
-	if strings.HasSuffix(baseUrl, "/") {
-		baseUrl = baseUrl[:len(baseUrl)-1]
+	return req, nil
+}
+
+type streamedBodyKey struct{}
+
+type sharedBodyKey struct{}
+
+// getBody parses the body again, ReaderBody rewinding or replaying its reader, and compresses it like the first one.
+func (r *Request) getBody(compress bool) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		body, err := r.BodyParser.Parse()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if compress {
+			body = gzipReader(body)
+		}
+
+		if closer, ok := body.(io.ReadCloser); ok {
+			return closer, nil
+		}
+
+		return io.NopCloser(body), nil
 	}
+}
 
-	if strings.HasPrefix(r.path, baseUrl) {
-		r.path = r.path[len(baseUrl)+1:]
+func closeReaders(readers ...io.Reader) {
+	for _, reader := range readers {
+		if closer, ok := reader.(io.Closer); ok {
+			_ = closer.Close()
+		}
 	}
+}
 
-	r.path = baseUrl + "/" + r.path
+// SetBaseUrl resolves the path against baseUrl when the request is sent, see joinURL.
+// Calling it again replaces the previous base instead of prefixing it twice.
+func (r *Request) SetBaseUrl(baseUrl string) *Request {
+	r.baseUrl = baseUrl
 
 	return r
 }
@@ -143,6 +440,43 @@ func (r *Request) SetContextBuilder(contextBuilder IContextBuilder) *Request {
 	return r
 }
 
+func (r *Request) SetClient(client *http.Client) *Request {
+	r.client = client
+
+	return r
+}
+
+// SetPathParam fills the {key} placeholder of the path with the path-escaped value.
+func (r *Request) SetPathParam(key, value string) *Request {
+	if r.pathParams == nil {
+		r.pathParams = map[string]string{}
+	}
+
+	r.pathParams[key] = value
+
+	return r
+}
+
+// WithContext sends the request with ctx, cancelling ctx aborts the in-flight Send.
+func (r *Request) WithContext(ctx context.Context) *Request {
+	r.ctx = ctx
+
+	return r
+}
+
+type contextValue struct {
+	key, value any
+}
+
+// WithValue attaches value to the context of the request under key, for middlewares to read it from req.Context().
+// Values are layered on top of the context given with WithContext, whatever the order of the calls. As with
+// context.WithValue, key should be of a type of your own.
+func (r *Request) WithValue(key, value any) *Request {
+	r.values = append(r.values, contextValue{key: key, value: value})
+
+	return r
+}
+
 type OptionRequest func(request *Request)
 
 func WithMethod(method HTTPMethod) OptionRequest {
@@ -163,12 +497,33 @@ func WithQuery(query IQuery) OptionRequest {
 	}
 }
 
+// WithRawQuery appends raw verbatim to the query of the URL, after the one of WithQuery, for syntaxes such as
+// filter[status]=active that query encoders escape. The caller is responsible for encoding it, it must not contain
+// spaces nor a '#'.
+func WithRawQuery(raw string) OptionRequest {
+	return func(request *Request) {
+		request.rawQuery = raw
+	}
+}
+
 func WithHeader(header IHeader) OptionRequest {
 	return func(request *Request) {
 		request.Header = header
 	}
 }
 
+// WithTimeout bounds the whole Send, retries included, to d. It replaces the context builder,
+// so whichever of WithTimeout and WithContextBuilder comes last wins. The context is cancelled once Send returns.
+func WithTimeout(d time.Duration) OptionRequest {
+	return WithContextBuilder(NewContextBuilder(d))
+}
+
+func WithContext(ctx context.Context) OptionRequest {
+	return func(request *Request) {
+		request.WithContext(ctx)
+	}
+}
+
 func WithContextBuilder(contextBuilder IContextBuilder) OptionRequest {
 	return func(request *Request) {
 		request.contextBuilder = contextBuilder
@@ -180,3 +535,54 @@ func WithCookies(cookies ...*http.Cookie) OptionRequest {
 		request.Cookies = cookies
 	}
 }
+
+func WithClient(client *http.Client) OptionRequest {
+	return func(request *Request) {
+		request.client = client
+	}
+}
+
+func WithPathParams(params map[string]string) OptionRequest {
+	return func(request *Request) {
+		for key, value := range params {
+			request.SetPathParam(key, value)
+		}
+	}
+}
+
+// WithAccept sends the accepted media types in order of preference, e.g. WithAccept("application/json", "text/xml")
+// sends "Accept: application/json, text/xml;q=0.9". Types carrying their own q-value are sent as they are.
+// It wins over an Accept header passed with WithHeader.
+func WithAccept(mediaTypes ...string) OptionRequest {
+	return func(request *Request) {
+		request.accept = acceptHeader(mediaTypes)
+	}
+}
+
+func acceptHeader(mediaTypes []string) string {
+	values := make([]string, 0, len(mediaTypes))
+
+	for i, mediaType := range mediaTypes {
+		if i > 0 && !strings.Contains(mediaType, ";q=") {
+			mediaType += ";q=" + strconv.FormatFloat(max(1-0.1*float64(i), 0.1), 'f', 1, 64)
+		}
+
+		values = append(values, mediaType)
+	}
+
+	return strings.Join(values, ", ")
+}
+
+// WithHost sends host as the Host of the request, to reach a virtual host or go through a load balancer,
+// while connecting to the host of the URL. It wins over a Host passed with WithHeader.
+func WithHost(host string) OptionRequest {
+	return func(request *Request) {
+		request.host = host
+	}
+}
+
+func WithUserAgent(userAgent string) OptionRequest {
+	return func(request *Request) {
+		request.userAgent = userAgent
+	}
+}
@@ -0,0 +1,295 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type countingTransport struct {
+	calls int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.calls++
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestRequest_SendWithClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{}
+	client := &http.Client{Transport: transport}
+
+	response, err := NewRequest(server.URL, WithClient(client)).Send()
+	if err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+	if !response.OK() {
+		t.Errorf("Send() returned status %d, expected 200", response.StatusCode)
+	}
+	if transport.calls != 1 {
+		t.Errorf("Send() used the injected client %d times, expected 1", transport.calls)
+	}
+
+	transport.calls = 0
+	_, _ = NewRequest(server.URL).SetClient(client).Send()
+	if transport.calls != 1 {
+		t.Errorf("SetClient() did not make Send() use the injected client")
+	}
+}
+
+func TestRequest_SendWithInvalidBody(t *testing.T) {
+	_, err := NewRequest("http://localhost", WithMethod(POST), WithBody(JSONBody(make(chan int)))).Send()
+	if err == nil {
+		t.Error("Send() did not return the body marshalling error")
+	}
+}
+
+func TestRequest_SendWithInvalidMethod(t *testing.T) {
+	pr, pw := io.Pipe()
+
+	response, err := NewRequest("http://localhost", WithMethod("BAD METHOD"), WithBody(ReaderBody(pr, "text/plain"))).Send()
+	if err == nil || response.StatusCode != 0 {
+		t.Errorf("Send() returned (%d, %v), expected an error response", response.StatusCode, err)
+	}
+
+	if _, err = pw.Write([]byte("data")); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("Send() left the request body open, writing to it returned %v", err)
+	}
+}
+
+func TestRequest_SendWithTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	_, err := NewRequest(server.URL, WithTimeout(50*time.Millisecond)).Send()
+	if err == nil {
+		t.Error("Send() did not fail after the timeout")
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("Send() took %v, expected it to stop after the 50ms timeout", time.Since(start))
+	}
+}
+
+func TestWithTimeoutPrecedence(t *testing.T) {
+	r := NewRequest("http://localhost", WithContextBuilder(NewContextBuilder(time.Minute)), WithTimeout(time.Second))
+	if r.contextBuilder != NewContextBuilder(time.Second) {
+		t.Error("WithTimeout() did not override an earlier WithContextBuilder()")
+	}
+
+	r = NewRequest("http://localhost", WithTimeout(time.Second), WithContextBuilder(NewContextBuilder(time.Minute)))
+	if r.contextBuilder != NewContextBuilder(time.Minute) {
+		t.Error("WithContextBuilder() did not override an earlier WithTimeout()")
+	}
+}
+
+func TestRequest_SendWithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	_, err := NewRequest(server.URL, WithContext(ctx)).Send()
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Send() returned %v, expected context.Canceled", err)
+	}
+
+	type key struct{}
+	ctx = context.WithValue(context.Background(), key{}, "value")
+	r := NewRequest(server.URL).WithContext(ctx)
+	if r.context().Ctx != ctx {
+		t.Error("Request WithContext() did not use the caller context as-is")
+	}
+
+	built := NewRequest(server.URL, WithContext(ctx), WithTimeout(time.Second)).context()
+	defer built.Cancel()
+	if _, ok := built.Ctx.Deadline(); !ok || built.Ctx.Value(key{}) != "value" {
+		t.Error("WithTimeout() did not layer its deadline on the caller context")
+	}
+}
+
+func TestRequest_SendWithContextIgnoresBuilder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		_, _ = w.Write([]byte("done"))
+	}))
+	defer server.Close()
+
+	r := NewRequest(server.URL, WithTimeout(10*time.Millisecond))
+
+	response, err := r.SendWithContext(context.Background())
+	if err != nil || string(response.Data) != "done" {
+		t.Errorf("SendWithContext() returned (%s, %v), expected the builder timeout not to apply", response.Data, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err = NewRequest(server.URL).SendWithContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("SendWithContext() returned %v, expected context.DeadlineExceeded", err)
+	}
+}
+
+func TestRequest_SendUserAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.UserAgent()))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		opts     []OptionRequest
+		expected string
+	}{
+		{nil, DefaultUserAgent},
+		{[]OptionRequest{WithHeader(NewHeader().Add("User-Agent", "from-header"))}, "from-header"},
+		{[]OptionRequest{WithUserAgent("custom/1.0"), WithHeader(NewHeader().Add("User-Agent", "from-header"))}, "custom/1.0"},
+	}
+
+	for _, test := range tests {
+		response, _ := NewRequest(server.URL, test.opts...).Send()
+		if string(response.Data) != test.expected {
+			t.Errorf("server received User-Agent %q, expected %q", response.Data, test.expected)
+		}
+	}
+}
+
+func TestWithAccept(t *testing.T) {
+	tests := map[string][]string{
+		"application/json": {"application/json"},
+		"application/json, text/xml;q=0.9, */*;q=0.8":        {"application/json", "text/xml", "*/*"},
+		"application/json, text/plain;q=0.5, text/xml;q=0.8": {"application/json", "text/plain;q=0.5", "text/xml"},
+	}
+
+	for expected, mediaTypes := range tests {
+		r := NewRequest("http://localhost", WithHeader(NewHeader().Add("Accept", "text/html")), WithAccept(mediaTypes...))
+
+		req, err := r.request(context.Background())
+		if err != nil || req.Header.Get("Accept") != expected {
+			t.Errorf("WithAccept(%v) sent %s (%v), expected %s", mediaTypes, req.Header.Get("Accept"), err, expected)
+		}
+	}
+
+	if !strings.HasSuffix(acceptHeader(make([]string, 12)), ";q=0.1") {
+		t.Error("acceptHeader() lowered the q-value below 0.1")
+	}
+}
+
+func TestRequest_Build(t *testing.T) {
+	ctx := context.WithValue(context.Background(), countingTransport{}, "value")
+
+	req, err := NewRequest("https://api.example.com/users/{id}",
+		WithMethod(POST),
+		WithPathParams(map[string]string{"id": "7"}),
+		WithQuery(NewMapQuery(map[string]any{"page": "2"})),
+		WithHeader(NewHeader().Add("X-Trace", "abc")),
+		WithBody(JSONBody(map[string]string{"name": "room"})),
+		WithCookies(&http.Cookie{Name: "session", Value: "abc"}),
+		WithContext(ctx)).Build()
+	if err != nil {
+		t.Fatalf("Build() returned unexpected error: %v", err)
+	}
+
+	body, _ := io.ReadAll(req.Body)
+	if req.Method != "POST" || req.URL.String() != "https://api.example.com/users/7?page=2" || req.Header.Get("X-Trace") != "abc" ||
+		req.Header.Get("Cookie") != "session=abc" || string(body) != "{\"name\":\"room\"}\n" || req.Context().Value(countingTransport{}) != "value" {
+		t.Errorf("Build() returned %s %s %v with body %s, expected the request as it would be sent", req.Method, req.URL, req.Header, body)
+	}
+
+	var requestErr *RequestError
+	if _, err = NewRequest("http://localhost", WithMethod(POST), WithBody(JSONBody(make(chan int)))).Build(); !errors.As(err, &requestErr) {
+		t.Errorf("Build() returned %v, expected a *RequestError", err)
+	}
+}
+
+func TestRequest_SendWithRawQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.URL.RawQuery))
+	}))
+	defer server.Close()
+
+	response, err := NewRequest(server.URL+"?a=1",
+		WithQuery(NewMapQuery(map[string]any{"page": "2"})),
+		WithRawQuery("filter[status]=active&sort=-created,name")).Send()
+	if expected := "a=1&page=2&filter[status]=active&sort=-created,name"; err != nil || string(response.Data) != expected {
+		t.Errorf("Send() sent query (%s, %v), expected %s", response.Data, err, expected)
+	}
+}
+
+func TestRequest_SendWithHost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Host + " " + r.RequestURI))
+	}))
+	defer server.Close()
+
+	response, err := NewRequest(server.URL+"/users?page=2", WithHost("api.internal:8443"), WithHeader(NewHeader().Add("Host", "ignored"))).Send()
+	if expected := "api.internal:8443 /users?page=2"; err != nil || string(response.Data) != expected {
+		t.Errorf("Send() WithHost sent (%s, %v), expected %s", response.Data, err, expected)
+	}
+
+	response, _ = NewRequest(server.URL, WithHeader(NewHeader().Add("Host", "header.internal"))).Send()
+	if string(response.Data) != "header.internal /" {
+		t.Errorf("Send() with a Host header sent %s, expected header.internal /", response.Data)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Host = "wrapped.internal"
+	if response, _ = FromHTTPRequest(req).Send(); string(response.Data) != "wrapped.internal /" {
+		t.Errorf("FromHTTPRequest() sent %s, expected the Host of the wrapped request", response.Data)
+	}
+}
+
+type correlationKey struct{}
+
+func TestRequest_WithValue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("X-Correlation-ID")))
+	}))
+	defer server.Close()
+
+	correlation := func(next RoundTripperFunc) RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if id, ok := req.Context().Value(correlationKey{}).(string); ok {
+				req.Header.Set("X-Correlation-ID", id)
+			}
+
+			return next(req)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	request := NewRequest(server.URL, WithMiddleware(correlation)).WithValue(correlationKey{}, "abc").WithContext(ctx)
+
+	response, err := request.Send()
+	if err != nil || string(response.Data) != "abc" {
+		t.Errorf("Send() returned (%s, %v), expected the middleware to read the value", response.Data, err)
+	}
+
+	cancel()
+	if _, err = request.Send(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Send() returned %v, expected the context given with WithContext kept", err)
+	}
+}
@@ -1,20 +1,39 @@
 package room
 
 import (
+	"bytes"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
+	"fmt"
 	"github.com/WEG-Technology/room/store"
 	"io"
 	"mime"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
+var ErrEmptyBody = errors.New("response body is empty")
+
+// ErrNoBody is returned by the decode helpers for a 204 or 304 response, which carries no body by definition.
+// It wraps ErrEmptyBody.
+var ErrNoBody = fmt.Errorf("response status has no body: %w", ErrEmptyBody)
+
+var ErrNotJSONObject = errors.New("response body is not a JSON object")
+
 type Response struct {
 	StatusCode int
 	Header     IHeader
 	Data       []byte
 	Request    RequestDTO
+	readErr    error
+	body       *responseBody
+	raw        *http.Response
+	startedAt  time.Time
+	timings    Timings
 }
 
 type RequestDTO struct {
@@ -24,24 +43,51 @@ type RequestDTO struct {
 	Method string
 }
 
+// NewResponse reads the whole body of response, decompressing gzip and deflate encoded bodies.
 func NewResponse(response *http.Response, request *http.Request) Response {
-	responseDTO := newResponse(response.Request).setHeader(response.Header).setData(response)
+	return newHTTPResponse(response, true, false, 0)
+}
+
+// newHTTPResponse leaves the body unread for the caller to consume when stream is set.
+// A positive maxSize bounds the body once decompressed, see WithMaxResponseSize.
+func newHTTPResponse(response *http.Response, decompress, stream bool, maxSize int64) Response {
+	if decompress {
+		decompressBody(response)
+	}
+
+	if maxSize > 0 {
+		limitBody(response, maxSize)
+	}
+
+	responseDTO := newResponse(response.Request).setHeader(response.Header)
+
+	if stream {
+		responseDTO.body = &responseBody{reader: response.Body}
+	} else {
+		responseDTO = responseDTO.setData(response)
+	}
 
 	responseDTO.StatusCode = response.StatusCode
+	responseDTO.raw = response
 
 	return responseDTO
 }
 
+// NewErrorResponse returns err wrapped in a *RequestError along with a response carrying it as data.
 func NewErrorResponse(request *http.Request, err error) (Response, error) {
 	responseDTO := newResponse(request)
 
 	errData, _ := json.Marshal(map[string]string{"runtime_error": err.Error()})
 	responseDTO.Data = errData
 
-	return responseDTO, err
+	return responseDTO, newRequestError(request, err)
 }
 
 func newResponse(request *http.Request) Response {
+	if request == nil {
+		return Response{Header: NewHeader(), Request: RequestDTO{Header: NewHeader()}}
+	}
+
 	responseDTO := Response{
 		Header: NewHeader(),
 		Request: RequestDTO{
@@ -60,11 +106,32 @@ func (r Response) OK() bool {
 	return r.StatusCode >= 200 && r.StatusCode < 300
 }
 
+// Status returns the status code, zero for a response built by NewErrorResponse.
+func (r Response) Status() int {
+	return r.StatusCode
+}
+
+func (r Response) IsSuccess() bool {
+	return r.OK()
+}
+
+func (r Response) IsRedirect() bool {
+	return r.StatusCode >= 300 && r.StatusCode < 400
+}
+
+func (r Response) IsClientError() bool {
+	return r.StatusCode >= 400 && r.StatusCode < 500
+}
+
+func (r Response) IsServerError() bool {
+	return r.StatusCode >= 500 && r.StatusCode < 600
+}
+
 func (r Response) setHeader(header http.Header) Response {
 	m := store.NewMapStore()
 
 	for key, values := range header {
-		m.Add(key, strings.Join(values, " "))
+		m.Add(key, storedHeaderValue(values))
 	}
 
 	r.Header = NewHeader(m)
@@ -72,11 +139,20 @@ func (r Response) setHeader(header http.Header) Response {
 	return r
 }
 
+// storedHeaderValue keeps a single value as a string, as headers were stored before repeated ones were supported.
+func storedHeaderValue(values []string) any {
+	if len(values) == 1 {
+		return values[0]
+	}
+
+	return slices.Clone(values)
+}
+
 func (r Response) setRequestHeader(header http.Header) Response {
 	m := store.NewMapStore()
 
 	for key, values := range header {
-		m.Add(key, strings.Join(values, " "))
+		m.Add(key, storedHeaderValue(values))
 	}
 
 	r.Request.Header = NewHeader(m)
@@ -86,7 +162,13 @@ func (r Response) setRequestHeader(header http.Header) Response {
 
 func (r Response) setData(response *http.Response) Response {
 	if response.Body != nil {
-		r.Data, _ = io.ReadAll(response.Body)
+		defer response.Body.Close()
+
+		if data, err := io.ReadAll(response.Body); err != nil {
+			r.Data, r.readErr = data, fmt.Errorf("read response body: %w", err)
+		} else {
+			r.Data = data
+		}
 	}
 
 	return r
@@ -95,7 +177,7 @@ func (r Response) setData(response *http.Response) Response {
 func (r Response) ResponseBodyOrFail() (map[string]any, error) {
 	var body map[string]any
 
-	err := NewDTOFactory(r.Header.Get(headerKeyContentType)).marshall(r.Data, &body)
+	err := NewDTOFactory(r.Header.Get(headerKeyContentType)).marshall(r.data(), &body)
 
 	return body, err
 }
@@ -103,28 +185,337 @@ func (r Response) ResponseBodyOrFail() (map[string]any, error) {
 func (r Response) ResponseBody() map[string]any {
 	var body map[string]any
 
-	if r.Data != nil {
-		_ = NewDTOFactory(r.Header.Get(headerKeyContentType)).marshall(r.Data, &body)
+	if data := r.data(); data != nil {
+		_ = NewDTOFactory(r.Header.Get(headerKeyContentType)).marshall(data, &body)
 	}
 
 	return body
 }
 
 func (r Response) DTO(v any) any {
-	if r.Data != nil {
-		_ = NewDTOFactory(r.Header.Get(headerKeyContentType)).marshall(r.Data, v)
+	if data := r.data(); data != nil {
+		_ = NewDTOFactory(r.Header.Get(headerKeyContentType)).marshall(data, v)
 	}
 
 	return v
 }
 
 func (r Response) DTOorFail(v any) error {
-	return NewDTOFactory(r.Header.Get(headerKeyContentType)).marshall(r.Data, v)
+	return NewDTOFactory(r.Header.Get(headerKeyContentType)).marshall(r.data(), v)
+}
+
+// JSON unmarshals the buffered response body into v.
+// It fails when the body is empty or the response declares a Content-Type that is not JSON.
+func (r Response) JSON(v any) error {
+	data, err := r.decodableBytes(jsonMediaTypes, isJSONMediaType)
+
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decode json response: %w", err)
+	}
+
+	return nil
 }
 
+// Map unmarshals a JSON object body into a generic map, for payloads without a struct to decode them into.
+// A body holding any other JSON value fails with ErrNotJSONObject.
+func (r Response) Map() (map[string]any, error) {
+	data, err := r.decodableBytes(jsonMediaTypes, isJSONMediaType)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if trimmed := bytes.TrimLeft(data, " \t\r\n"); len(trimmed) > 0 && trimmed[0] != '{' && json.Valid(trimmed) {
+		return nil, fmt.Errorf("%w: body starts with %q", ErrNotJSONObject, trimmed[0])
+	}
+
+	var m map[string]any
+
+	if err = json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("decode json response: %w", err)
+	}
+
+	return m, nil
+}
+
+// DecodeJSON decodes the response body into v with a json.Decoder.
+// A body left unread by WithStream is decoded as it arrives and closed afterwards.
+func (r Response) DecodeJSON(v any) error {
+	if r.noBodyStatus() {
+		_ = r.Close()
+
+		return ErrNoBody
+	}
+
+	if err := r.expectMediaType(jsonMediaTypes, isJSONMediaType); err != nil {
+		return err
+	}
+
+	reader, err := r.bodyReader()
+
+	if err != nil {
+		return err
+	}
+
+	defer r.Close()
+
+	if err = json.NewDecoder(reader).Decode(v); err != nil {
+		if errors.Is(err, io.EOF) {
+			return ErrEmptyBody
+		}
+
+		return fmt.Errorf("decode json response: %w", err)
+	}
+
+	return nil
+}
+
+// XML unmarshals the buffered response body into v.
+// It fails when the body is empty or the response declares a Content-Type that is not XML.
+func (r Response) XML(v any) error {
+	data, err := r.decodableBytes(xmlMediaTypes, isXMLMediaType)
+
+	if err != nil {
+		return err
+	}
+
+	if err = xml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decode xml response: %w", err)
+	}
+
+	return nil
+}
+
+// Bytes returns the response body. Send reads the body in full and closes it before returning,
+// so Bytes, String, JSON and XML can all be called on the same response. A body left unread by WithStream
+// is read and closed on the first call and buffered for the following ones.
+func (r Response) Bytes() ([]byte, error) {
+	if r.body != nil {
+		return r.body.bytes()
+	}
+
+	return r.Data, r.readErr
+}
+
+func (r Response) data() []byte {
+	data, _ := r.Bytes()
+
+	return data
+}
+
+// String returns the response body as a string, see Bytes.
+func (r Response) String() (string, error) {
+	data, err := r.Bytes()
+
+	return string(data), err
+}
+
+// decodableBytes returns a non-empty body whose Content-Type passes match.
+func (r Response) decodableBytes(expected string, match func(mediaType string) bool) ([]byte, error) {
+	if r.noBodyStatus() {
+		return nil, ErrNoBody
+	}
+
+	data, err := r.Bytes()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.expectMediaType(expected, match); err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, ErrEmptyBody
+	}
+
+	return data, nil
+}
+
+// bodyReader streams a body left unread by WithStream, and reads from the buffered body otherwise.
+func (r Response) bodyReader() (io.Reader, error) {
+	if r.body != nil {
+		if reader, ok := r.body.take(); ok {
+			return reader, nil
+		}
+	}
+
+	data, err := r.Bytes()
+
+	return bytes.NewReader(data), err
+}
+
+// expectMediaType accepts a missing Content-Type and otherwise checks its media type with match.
+func (r Response) expectMediaType(expected string, match func(mediaType string) bool) error {
+	contentType := r.ContentType()
+
+	if contentType == "" {
+		return nil
+	}
+
+	if mediaType, _, err := mime.ParseMediaType(contentType); err != nil || !match(mediaType) {
+		return r.contentTypeError(expected, contentType)
+	}
+
+	return nil
+}
+
+// ExpectContentType fails with a *ContentTypeError unless the media type of the response is one of types,
+// a type such as "text/*" matching any subtype. Unlike the decode helpers it rejects a missing Content-Type.
+func (r Response) ExpectContentType(types ...string) error {
+	contentType := r.ContentType()
+	mediaType, _, err := mime.ParseMediaType(contentType)
+
+	if err == nil {
+		for _, expected := range types {
+			if strings.EqualFold(expected, mediaType) ||
+				strings.HasSuffix(expected, "/*") && strings.HasPrefix(mediaType, strings.ToLower(strings.TrimSuffix(expected, "*"))) {
+				return nil
+			}
+		}
+	}
+
+	return r.contentTypeError(strings.Join(types, ", "), contentType)
+}
+
+// ContentType returns the Content-Type header of the response.
+func (r Response) ContentType() string {
+	return r.HeaderValue(headerKeyContentType)
+}
+
+// HeaderValue returns the first value of the response header key, the Header field holds them all.
+func (r Response) HeaderValue(key string) string {
+	if r.raw != nil {
+		return r.raw.Header.Get(key)
+	}
+
+	if r.Header == nil {
+		return ""
+	}
+
+	return r.Header.Get(key)
+}
+
+// Headers returns a copy of the response headers, values of repeated headers kept apart.
+func (r Response) Headers() http.Header {
+	if r.raw != nil {
+		return r.raw.Header.Clone()
+	}
+
+	headers := http.Header{}
+
+	if r.Header != nil {
+		asHeader(r.Header).Each(headers.Add)
+	}
+
+	return headers
+}
+
+// HasBody reports whether the response may carry a body. A HEAD request, a 1xx, 204 or 304 status and a declared
+// Content-Length of 0 have none, nor has an empty buffered body. A body left unread by WithStream is not read to tell.
+func (r Response) HasBody() bool {
+	if r.noBodyStatus() || r.StatusCode >= 100 && r.StatusCode < 200 || r.Request.Method == http.MethodHead || r.ContentLength() == 0 {
+		return false
+	}
+
+	if r.body != nil {
+		if data := r.body.peek(); data != nil {
+			return len(data) > 0
+		}
+
+		return true
+	}
+
+	return len(r.Data) > 0
+}
+
+func (r Response) noBodyStatus() bool {
+	return r.StatusCode == http.StatusNoContent || r.StatusCode == http.StatusNotModified
+}
+
+// ContentLength returns the length the server declared for the body, -1 when unknown
+// or once a compressed body was decompressed.
+func (r Response) ContentLength() int64 {
+	if r.raw != nil {
+		return r.raw.ContentLength
+	}
+
+	if length, err := strconv.ParseInt(r.HeaderValue("Content-Length"), 10, 64); err == nil {
+		return length
+	}
+
+	return -1
+}
+
+const contentTypeSnippetSize = 128
+
+// ContentTypeError reports a response whose Content-Type is not the expected one, with the start of its body
+// to tell an HTML error page from the expected payload. Snippet is empty for a body left unread by WithStream.
+type ContentTypeError struct {
+	Expected    string
+	ContentType string
+	Snippet     string
+}
+
+func (e *ContentTypeError) Error() string {
+	message := fmt.Sprintf("unexpected response content type %q, expected %s", e.ContentType, e.Expected)
+
+	if e.Snippet != "" {
+		message += fmt.Sprintf(": %q", e.Snippet)
+	}
+
+	return message
+}
+
+func (r Response) contentTypeError(expected, contentType string) error {
+	data := r.Data
+
+	if r.body != nil {
+		data = r.body.peek()
+	}
+
+	if len(data) > contentTypeSnippetSize {
+		data = data[:contentTypeSnippetSize]
+	}
+
+	return &ContentTypeError{Expected: expected, ContentType: contentType, Snippet: string(data)}
+}
+
+const (
+	jsonMediaTypes = "application/json or +json"
+	xmlMediaTypes  = "text/xml, application/xml or +xml"
+)
+
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == headerValueApplicationJson || strings.HasSuffix(mediaType, "+json")
+}
+
+func isXMLMediaType(mediaType string) bool {
+	return mediaType == headerValueTextXML || mediaType == headerValueApplicationXML || strings.HasSuffix(mediaType, "+xml")
+}
+
+// setRequestData reads the sent body again through GetBody, the transport already consumed Body.
+// A streamed body, a file or a pipe for instance, is not read again.
 func (r Response) setRequestData(request *http.Request) Response {
-	if request.Body != nil {
-		r.Request.Data, _ = io.ReadAll(request.Body)
+	if request.Context().Value(streamedBodyKey{}) != nil {
+		return r
+	}
+
+	body := request.Body
+
+	if request.GetBody != nil {
+		if replayed, err := request.GetBody(); err == nil {
+			body = replayed
+		}
+	}
+
+	if body != nil {
+		r.Request.Data, _ = io.ReadAll(body)
 	}
 
 	return r
@@ -221,16 +612,16 @@ func getBoundary(contentType string) string {
 }
 
 func DTO[T any](response Response, v T) T {
-	if response.Data != nil {
-		_ = NewDTOFactory(response.Header.Get(headerKeyContentType)).marshall(response.Data, v)
+	if data := response.data(); data != nil {
+		_ = NewDTOFactory(response.Header.Get(headerKeyContentType)).marshall(data, v)
 	}
 
 	return v
 }
 
 func DTOorFail[T any](response Response, v T) (T, error) {
-	if response.Data != nil {
-		err := NewDTOFactory(response.Header.Get(headerKeyContentType)).marshall(response.Data, v)
+	if data := response.data(); data != nil {
+		err := NewDTOFactory(response.Header.Get(headerKeyContentType)).marshall(data, v)
 
 		if err != nil {
 			return v, err
@@ -0,0 +1,144 @@
+package room
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+var ErrBodyConsumed = errors.New("response body has already been streamed")
+
+// WithStream leaves the response body unread by Send so it can be streamed with Save or DecodeJSON.
+// The caller must consume or Close the response, the request context is only released then.
+func WithStream() OptionRequest {
+	return func(request *Request) {
+		request.stream = true
+	}
+}
+
+// responseBody holds a body left unread by WithStream. It is shared by every copy of the Response.
+type responseBody struct {
+	mu       sync.Mutex
+	reader   io.ReadCloser
+	onClose  func()
+	data     []byte
+	err      error
+	buffered bool
+	taken    bool
+	closed   bool
+}
+
+// peek returns the body if it was already buffered, without reading it.
+func (b *responseBody) peek() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.data
+}
+
+func (b *responseBody) bytes() ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.buffered {
+		if b.taken {
+			return nil, ErrBodyConsumed
+		}
+
+		data, err := io.ReadAll(b.reader)
+
+		if err != nil {
+			err = fmt.Errorf("read response body: %w", err)
+		}
+
+		b.data, b.err, b.buffered = data, err, true
+
+		b.closeLocked()
+	}
+
+	return b.data, b.err
+}
+
+// take hands the unread stream over to the caller, after which it can no longer be buffered.
+func (b *responseBody) take() (io.Reader, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.buffered || b.taken {
+		return nil, false
+	}
+
+	b.taken = true
+
+	return b.reader, true
+}
+
+func (b *responseBody) close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.closeLocked()
+}
+
+func (b *responseBody) closeLocked() error {
+	if b.closed {
+		return nil
+	}
+
+	b.closed = true
+
+	err := b.reader.Close()
+
+	if b.onClose != nil {
+		b.onClose()
+	}
+
+	return err
+}
+
+// Close releases a body left unread by WithStream. It is a no-op for buffered responses.
+func (r Response) Close() error {
+	if r.body == nil {
+		return nil
+	}
+
+	return r.body.close()
+}
+
+// Save copies the body to w and closes it, returning the number of bytes written.
+// With WithStream the body is copied as it arrives without being held in memory.
+func (r Response) Save(w io.Writer) (int64, error) {
+	reader, err := r.bodyReader()
+
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := io.Copy(w, reader)
+
+	if closeErr := r.Close(); err == nil {
+		err = closeErr
+	}
+
+	return n, err
+}
+
+// SaveFile saves the body to the file at path, creating or truncating it.
+func (r Response) SaveFile(path string) error {
+	f, err := os.Create(path)
+
+	if err != nil {
+		_ = r.Close()
+		return err
+	}
+
+	_, err = r.Save(f)
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
@@ -0,0 +1,88 @@
+package room
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newBodyServer(body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+func TestResponse_SaveWithStream(t *testing.T) {
+	content := strings.Repeat("room", 1<<16)
+	server := newBodyServer(content)
+	defer server.Close()
+
+	response, err := NewRequest(server.URL, WithStream()).Send()
+	if err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+	if response.Data != nil {
+		t.Error("Send() buffered the body despite WithStream()")
+	}
+
+	var out bytes.Buffer
+	n, err := response.Save(&out)
+	if err != nil || n != int64(len(content)) || out.String() != content {
+		t.Errorf("Response Save() returned (%d, %v), expected (%d, nil)", n, err, len(content))
+	}
+
+	if _, err = response.Bytes(); !errors.Is(err, ErrBodyConsumed) {
+		t.Errorf("Response Bytes() after Save() returned %v, expected ErrBodyConsumed", err)
+	}
+}
+
+func TestResponse_SaveBuffered(t *testing.T) {
+	server := newBodyServer("buffered")
+	defer server.Close()
+
+	response, _ := NewRequest(server.URL).Send()
+
+	var out bytes.Buffer
+	if n, err := response.Save(&out); err != nil || n != 8 || out.String() != "buffered" {
+		t.Errorf("Response Save() returned (%d, %v, %q), expected (8, nil, buffered)", n, err, out.String())
+	}
+}
+
+func TestResponse_SaveFile(t *testing.T) {
+	server := newBodyServer("file content")
+	defer server.Close()
+
+	response, _ := NewRequest(server.URL, WithStream()).Send()
+
+	path := filepath.Join(t.TempDir(), "download.txt")
+	if err := response.SaveFile(path); err != nil {
+		t.Fatalf("Response SaveFile() returned unexpected error: %v", err)
+	}
+
+	content, _ := os.ReadFile(path)
+	if string(content) != "file content" {
+		t.Errorf("Response SaveFile() wrote %q, expected %q", content, "file content")
+	}
+}
+
+func TestResponse_BytesWithStream(t *testing.T) {
+	server := newBodyServer(`{"id":1}`)
+	defer server.Close()
+
+	response, _ := NewRequest(server.URL, WithStream()).Send()
+
+	for i := 0; i < 2; i++ {
+		if data, err := response.Bytes(); err != nil || string(data) != `{"id":1}` {
+			t.Errorf("Response Bytes() returned (%q, %v) on read %d", data, err, i+1)
+		}
+	}
+
+	if body := response.ResponseBody(); body["id"] != float64(1) {
+		t.Errorf("Response ResponseBody() returned %v for a streamed body", body)
+	}
+}
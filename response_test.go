@@ -1,9 +1,12 @@
 package room
 
 import (
+	"errors"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -84,3 +87,257 @@ func TestResponse_SetData(t *testing.T) {
 		t.Error("Response SetData() did not set the response data correctly")
 	}
 }
+
+func newTestResponse(contentType string, data string) Response {
+	response := Response{Header: NewHeader(), Data: []byte(data)}
+	if contentType != "" {
+		response.Header.Add(headerKeyContentType, contentType)
+	}
+
+	return response
+}
+
+// TestResponse_JSON tests the JSON method of the Response struct.
+func TestResponse_JSON(t *testing.T) {
+	var v struct {
+		ID int `json:"id"`
+	}
+
+	response := newTestResponse("application/json; charset=utf-8", `{"id":5}`)
+	if err := response.JSON(&v); err != nil || v.ID != 5 {
+		t.Errorf("Response JSON() returned (%v, %d), expected (nil, 5)", err, v.ID)
+	}
+	if response.StatusCode != 0 || len(response.Data) == 0 {
+		t.Error("Response JSON() consumed the buffered body")
+	}
+
+	response = newTestResponse("text/html", `<html></html>`)
+	if err := response.JSON(&v); err == nil {
+		t.Error("Response JSON() did not fail for a text/html content type")
+	}
+
+	response = newTestResponse("application/json", "")
+	if err := response.JSON(&v); !errors.Is(err, ErrEmptyBody) {
+		t.Errorf("Response JSON() returned %v for an empty body, expected ErrEmptyBody", err)
+	}
+}
+
+func TestResponse_Map(t *testing.T) {
+	m, err := newTestResponse("application/json", ` {"id":5,"tags":["a"]}`).Map()
+	if err != nil || m["id"] != float64(5) || len(m["tags"].([]any)) != 1 {
+		t.Errorf("Response Map() returned (%v, %v), expected the object", m, err)
+	}
+
+	for _, body := range []string{`[{"id":5}]`, `"id"`, "null"} {
+		if _, err = newTestResponse("application/json", body).Map(); !errors.Is(err, ErrNotJSONObject) {
+			t.Errorf("Response Map() returned %v for %s, expected ErrNotJSONObject", err, body)
+		}
+	}
+
+	if _, err = newTestResponse("application/json", `{"id":`).Map(); err == nil || errors.Is(err, ErrNotJSONObject) {
+		t.Errorf("Response Map() returned %v for invalid JSON, expected a decoding error", err)
+	}
+}
+
+func TestResponse_HasBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status, _ := strconv.Atoi(r.URL.Query().Get("status"))
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(r.URL.Query().Get("body")))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		query   string
+		method  HTTPMethod
+		hasBody bool
+	}{
+		{"status=200&body=%7B%7D", GET, true},
+		{"status=200", GET, false},
+		{"status=204", GET, false},
+		{"status=304", GET, false},
+		{"status=200&body=%7B%7D", HEAD, false},
+	}
+
+	for _, test := range tests {
+		response, err := NewRequest(server.URL+"?"+test.query, WithMethod(test.method)).Send()
+		if err != nil || response.HasBody() != test.hasBody {
+			t.Errorf("HasBody() of %s %s returned %t (%v), expected %t", test.method, test.query, response.HasBody(), err, test.hasBody)
+		}
+
+		streamed, _ := NewRequest(server.URL+"?"+test.query, WithMethod(test.method), WithStream()).Send()
+		if test.hasBody != streamed.HasBody() {
+			t.Errorf("HasBody() of a streamed %s %s returned %t, expected %t", test.method, test.query, streamed.HasBody(), test.hasBody)
+		}
+		_ = streamed.Close()
+	}
+
+	var v map[string]any
+	response, _ := NewRequest(server.URL + "?status=204").Send()
+	if err := response.JSON(&v); !errors.Is(err, ErrNoBody) || !errors.Is(err, ErrEmptyBody) {
+		t.Errorf("JSON() of a 204 response returned %v, expected ErrNoBody", err)
+	}
+	if err := response.DecodeJSON(&v); !errors.Is(err, ErrNoBody) {
+		t.Errorf("DecodeJSON() of a 204 response returned %v, expected ErrNoBody", err)
+	}
+	if response, _ = NewRequest(server.URL + "?status=200").Send(); errors.Is(response.JSON(&v), ErrNoBody) {
+		t.Error("JSON() of an empty 200 response returned ErrNoBody, expected ErrEmptyBody")
+	}
+}
+
+// TestResponse_DecodeJSON tests the DecodeJSON method of the Response struct.
+func TestResponse_DecodeJSON(t *testing.T) {
+	var v struct {
+		ID int `json:"id"`
+	}
+
+	response := newTestResponse("application/problem+json", `{"id":7}`)
+	if err := response.DecodeJSON(&v); err != nil || v.ID != 7 {
+		t.Errorf("Response DecodeJSON() returned (%v, %d), expected (nil, 7)", err, v.ID)
+	}
+
+	response = newTestResponse("", "")
+	if err := response.DecodeJSON(&v); !errors.Is(err, ErrEmptyBody) {
+		t.Errorf("Response DecodeJSON() returned %v for an empty body, expected ErrEmptyBody", err)
+	}
+}
+
+// TestResponse_XML tests the XML method of the Response struct.
+func TestResponse_XML(t *testing.T) {
+	var v struct {
+		ID int `xml:"id"`
+	}
+
+	response := newTestResponse("text/xml; charset=utf-8", `<item><id>3</id></item>`)
+	if err := response.XML(&v); err != nil || v.ID != 3 {
+		t.Errorf("Response XML() returned (%v, %d), expected (nil, 3)", err, v.ID)
+	}
+
+	response = newTestResponse("application/xml", `<item><id>3</item>`)
+	if err := response.XML(&v); err == nil {
+		t.Error("Response XML() did not fail for malformed XML")
+	}
+
+	response = newTestResponse("application/json", `{"id":3}`)
+	if err := response.XML(&v); err == nil {
+		t.Error("Response XML() did not fail for an application/json content type")
+	}
+}
+
+// TestResponse_Bytes tests the Bytes and String methods of the Response struct.
+func TestResponse_Bytes(t *testing.T) {
+	response := newTestResponse("application/json", `{"id":1}`)
+
+	for i := 0; i < 2; i++ {
+		data, err := response.Bytes()
+		if err != nil || string(data) != `{"id":1}` {
+			t.Errorf("Response Bytes() returned (%q, %v) on read %d", data, err, i+1)
+		}
+	}
+
+	if s, err := response.String(); err != nil || s != `{"id":1}` {
+		t.Errorf("Response String() returned (%q, %v), expected the body", s, err)
+	}
+
+	var v struct {
+		ID int `json:"id"`
+	}
+	if err := response.JSON(&v); err != nil || v.ID != 1 {
+		t.Errorf("Response JSON() after Bytes() returned (%v, %d), expected (nil, 1)", err, v.ID)
+	}
+}
+
+// TestResponse_StatusClasses tests the status class helpers of the Response struct.
+func TestResponse_StatusClasses(t *testing.T) {
+	tests := []struct {
+		status      int
+		success     bool
+		redirect    bool
+		clientError bool
+		serverError bool
+	}{
+		{0, false, false, false, false},
+		{204, true, false, false, false},
+		{302, false, true, false, false},
+		{404, false, false, true, false},
+		{503, false, false, false, true},
+	}
+
+	for _, test := range tests {
+		response := Response{StatusCode: test.status}
+		if response.Status() != test.status {
+			t.Errorf("Response Status() returned %d, expected %d", response.Status(), test.status)
+		}
+		if response.IsSuccess() != test.success || response.IsRedirect() != test.redirect ||
+			response.IsClientError() != test.clientError || response.IsServerError() != test.serverError {
+			t.Errorf("Response status classes for %d are (%t, %t, %t, %t), expected (%t, %t, %t, %t)", test.status,
+				response.IsSuccess(), response.IsRedirect(), response.IsClientError(), response.IsServerError(),
+				test.success, test.redirect, test.clientError, test.serverError)
+		}
+	}
+
+	response, _ := NewErrorResponse(&http.Request{Method: http.MethodGet, URL: &url.URL{Scheme: "http", Host: "localhost"}}, io.EOF)
+	if response.IsSuccess() || response.IsServerError() {
+		t.Error("Response built by NewErrorResponse() reported a status class")
+	}
+}
+
+func TestResponse_ExpectContentType(t *testing.T) {
+	response := newTestResponse("application/problem+json; charset=utf-8", "{}")
+	if err := response.ExpectContentType("application/json", "application/problem+json"); err != nil {
+		t.Errorf("ExpectContentType() returned %v, expected the second type to match", err)
+	}
+	if err := response.ExpectContentType("text/html", "application/*"); err != nil {
+		t.Errorf("ExpectContentType() returned %v, expected application/* to match", err)
+	}
+	if err := newTestResponse("", "{}").ExpectContentType("application/json"); err == nil {
+		t.Error("ExpectContentType() accepted a missing Content-Type")
+	}
+
+	page := "<html><body>" + strings.Repeat("Bad Gateway ", 20) + "</body></html>"
+	err := newTestResponse("text/html", page).JSON(&map[string]any{})
+
+	var contentTypeErr *ContentTypeError
+	if !errors.As(err, &contentTypeErr) {
+		t.Fatalf("JSON() returned %v, expected a *ContentTypeError", err)
+	}
+	if contentTypeErr.ContentType != "text/html" || contentTypeErr.Snippet != page[:contentTypeSnippetSize] {
+		t.Errorf("JSON() returned %+v, expected the actual type and the start of the body", contentTypeErr)
+	}
+	if !strings.Contains(err.Error(), `"text/html"`) || !strings.Contains(err.Error(), "<html><body>Bad Gateway") {
+		t.Errorf("JSON() returned error %q, expected the type and a snippet in the message", err.Error())
+	}
+}
+
+func TestResponse_HeaderAccessors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("X-Tag", "a")
+		w.Header().Add("X-Tag", "b")
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", "2")
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	response, err := NewRequest(server.URL).Send()
+	if err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+
+	if response.HeaderValue("x-tag") != "a" || len(response.Headers()["X-Tag"]) != 2 {
+		t.Errorf("HeaderValue() returned %s and Headers() %v, expected the values kept apart", response.HeaderValue("x-tag"), response.Headers()["X-Tag"])
+	}
+	if response.ContentType() != "application/json" || response.ContentLength() != 2 {
+		t.Errorf("ContentType() returned %s and ContentLength() %d, expected application/json and 2", response.ContentType(), response.ContentLength())
+	}
+
+	response.Headers().Set("X-Tag", "changed")
+	if response.HeaderValue("X-Tag") != "a" {
+		t.Error("Headers() did not return a copy")
+	}
+
+	built := newTestResponse("text/plain", "")
+	if built.ContentType() != "text/plain" || built.ContentLength() != -1 || built.Headers().Get("Content-Type") != "text/plain" {
+		t.Errorf("accessors returned %s, %d for a response built without an exchange", built.ContentType(), built.ContentLength())
+	}
+}
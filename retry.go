@@ -0,0 +1,164 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BackoffFunc returns how long to wait before the next attempt, attempt starts at 1.
+type BackoffFunc func(attempt int) time.Duration
+
+// RetryCondition reports whether a round-trip outcome should be retried.
+type RetryCondition func(response *http.Response, err error) bool
+
+// DefaultRetryCondition retries network errors and 502, 503 and 504 responses.
+var DefaultRetryCondition = AnyRetryCondition(RetryOnNetworkError, RetryOnStatus(http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout))
+
+// RetryOnNetworkError retries failed round-trips unless the request context was cancelled or timed out,
+// or a circuit breaker refused to send them.
+func RetryOnNetworkError(response *http.Response, err error) bool {
+	return err != nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, ErrCircuitOpen)
+}
+
+// RetryOnStatus retries responses carrying one of the given status codes.
+func RetryOnStatus(codes ...int) RetryCondition {
+	return func(response *http.Response, err error) bool {
+		return err == nil && response != nil && slices.Contains(codes, response.StatusCode)
+	}
+}
+
+// AnyRetryCondition retries when at least one of the conditions does.
+func AnyRetryCondition(conditions ...RetryCondition) RetryCondition {
+	return func(response *http.Response, err error) bool {
+		for _, condition := range conditions {
+			if condition(response, err) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+type retryPolicy struct {
+	maxAttempts int
+	backoff     BackoffFunc
+	condition   RetryCondition
+}
+
+func newRetryPolicy() *retryPolicy {
+	return &retryPolicy{maxAttempts: 1, condition: DefaultRetryCondition}
+}
+
+func (p *retryPolicy) shouldRetry(attempt int, response *http.Response, err error) bool {
+	if p == nil || attempt >= p.maxAttempts {
+		return false
+	}
+
+	return p.condition(response, err)
+}
+
+// wait sleeps for the Retry-After delay of a 429 or 503 response, or for the configured backoff otherwise.
+func (p *retryPolicy) wait(ctx context.Context, attempt int, response *http.Response) error {
+	var delay time.Duration
+
+	if after, ok := retryAfter(response); ok {
+		delay = after
+	} else if p.backoff != nil {
+		delay = p.backoff(attempt)
+	}
+
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryAfter parses the Retry-After header of a 429 or 503 response in either delay-seconds or HTTP-date form.
+func retryAfter(response *http.Response) (time.Duration, bool) {
+	if response == nil || (response.StatusCode != http.StatusTooManyRequests && response.StatusCode != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+
+	value := strings.TrimSpace(response.Header.Get("Retry-After"))
+
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
+
+// discardBody drains and closes a response that is dropped in favour of a retry so its connection can be reused.
+func discardBody(response *http.Response) {
+	if response != nil && response.Body != nil {
+		_, _ = io.Copy(io.Discard, response.Body)
+		_ = response.Body.Close()
+	}
+}
+
+func (r *Request) retryPolicy() *retryPolicy {
+	if r.retry == nil {
+		r.retry = newRetryPolicy()
+	}
+
+	return r.retry
+}
+
+// WithRetry re-issues the request up to maxAttempts times in total, waiting backoff between attempts.
+// The body is parsed again from the BodyParser on every attempt.
+func WithRetry(maxAttempts int, backoff BackoffFunc) OptionRequest {
+	return func(request *Request) {
+		policy := request.retryPolicy()
+		policy.maxAttempts = maxAttempts
+		policy.backoff = backoff
+	}
+}
+
+// WithRetryCondition replaces DefaultRetryCondition for the request.
+func WithRetryCondition(condition RetryCondition) OptionRequest {
+	return func(request *Request) {
+		request.retryPolicy().condition = condition
+	}
+}
+
+// WithRetryOnStatus retries network errors and only the given status codes.
+// 429 and 503 responses carrying a Retry-After header wait for that delay instead of the backoff.
+// Without codes the request falls back to DefaultRetryCondition.
+func WithRetryOnStatus(codes ...int) OptionRequest {
+	return func(request *Request) {
+		if len(codes) == 0 {
+			request.retryPolicy().condition = DefaultRetryCondition
+			return
+		}
+
+		request.retryPolicy().condition = AnyRetryCondition(RetryOnNetworkError, RetryOnStatus(codes...))
+	}
+}
@@ -0,0 +1,149 @@
+package room
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequest_SendWithRetry(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "{\"key\":\"value\"}\n" {
+			t.Errorf("attempt %d received body %q", calls, body)
+		}
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	response, err := NewRequest(server.URL,
+		WithMethod(POST),
+		WithBody(NewJsonBodyParser(map[string]any{"key": "value"})),
+		WithRetry(3, func(attempt int) time.Duration { return time.Millisecond }),
+	).Send()
+
+	if err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Send() returned status %d, expected 200", response.StatusCode)
+	}
+	if calls != 3 {
+		t.Errorf("Send() made %d attempts, expected 3", calls)
+	}
+}
+
+func TestRequest_SendWithRetryExhausted(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	response, _ := NewRequest(server.URL, WithRetry(3, nil)).Send()
+
+	if calls != 1 {
+		t.Errorf("Send() retried a 500 response %d times, expected no retry", calls-1)
+	}
+	if response.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Send() returned status %d, expected 500", response.StatusCode)
+	}
+}
+
+func TestRequest_SendWithRetryStopsOnCancel(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := NewRequest(server.URL,
+		WithContextBuilder(NewContextBuilder(50*time.Millisecond)),
+		WithRetry(10, func(attempt int) time.Duration { return time.Second }),
+	).Send()
+
+	if err == nil {
+		t.Error("Send() did not return an error when the context expired during backoff")
+	}
+	if calls != 1 {
+		t.Errorf("Send() made %d attempts, expected 1", calls)
+	}
+}
+
+func TestDefaultRetryCondition(t *testing.T) {
+	tests := []struct {
+		status   int
+		expected bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusInternalServerError, false},
+		{http.StatusBadGateway, true},
+		{http.StatusServiceUnavailable, true},
+		{http.StatusGatewayTimeout, true},
+	}
+
+	for _, test := range tests {
+		result := DefaultRetryCondition(&http.Response{StatusCode: test.status}, nil)
+		if result != test.expected {
+			t.Errorf("DefaultRetryCondition() returned %t for status %d, expected %t", result, test.status, test.expected)
+		}
+	}
+
+	if !DefaultRetryCondition(nil, io.ErrUnexpectedEOF) {
+		t.Error("DefaultRetryCondition() did not retry a network error")
+	}
+}
+
+func TestRequest_SendWithRetryOnStatus(t *testing.T) {
+	statuses := []int{http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusOK}
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if statuses[calls] == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "0")
+		}
+		w.WriteHeader(statuses[calls])
+		calls++
+	}))
+	defer server.Close()
+
+	response, _ := NewRequest(server.URL,
+		WithRetry(5, ConstantBackoff(time.Hour)),
+		WithRetryOnStatus(http.StatusTooManyRequests, http.StatusServiceUnavailable),
+	).Send()
+
+	if calls != 2 {
+		t.Errorf("Send() made %d attempts, expected 2", calls)
+	}
+	if response.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Send() returned status %d, expected 500", response.StatusCode)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	response := &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}}
+
+	response.Header.Set("Retry-After", "2")
+	if delay, ok := retryAfter(response); !ok || delay != 2*time.Second {
+		t.Errorf("retryAfter() returned (%v, %t), expected (%v, true)", delay, ok, 2*time.Second)
+	}
+
+	response.Header.Set("Retry-After", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	if delay, ok := retryAfter(response); !ok || delay != 0 {
+		t.Errorf("retryAfter() returned (%v, %t) for a past date, expected (0, true)", delay, ok)
+	}
+
+	response.StatusCode = http.StatusBadGateway
+	if _, ok := retryAfter(response); ok {
+		t.Error("retryAfter() honored Retry-After on a 502 response")
+	}
+}
@@ -0,0 +1,31 @@
+package room
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAuthRoom_Send(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth" {
+			w.Header().Set(headerKeyContentType, headerValueApplicationJson)
+			_, _ = w.Write([]byte(`{"token":"abc"}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(strings.Join(r.Header.Values("Authorization"), ",")))
+	}))
+	defer server.Close()
+
+	connector := NewConnector(server.URL, WithHeaderConnector(NewHeader()))
+	authRoom := NewAuthRoom(connector, NewRequest("/auth", WithMethod(POST)), "token")
+
+	for i := 1; i <= 2; i++ {
+		response, err := authRoom.Send(NewRequest("/data"))
+		if err != nil || string(response.Data) != "Bearer abc" {
+			t.Errorf("AuthRoom Send() %d sent Authorization (%s, %v), expected a single Bearer abc", i, response.Data, err)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Package roomtest asserts on room responses in tests:
+//
+//	response, err := room.NewRequest(server.URL + "/users/5").Send()
+//
+//	roomtest.Assert(t, response).
+//		Status(http.StatusOK).
+//		HeaderEquals("Content-Type", "application/json").
+//		JSONField("id", 5)
+//
+// Assert fails the test on every failed check and goes on with the next ones, Check collects the failures instead.
+package roomtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/WEG-Technology/room"
+)
+
+// Assertion runs checks against a response, reporting each failure to the test or collecting it.
+type Assertion struct {
+	response room.Response
+	t        testing.TB
+	errs     []error
+}
+
+// Assert reports every failed check to t with t.Errorf.
+func Assert(t testing.TB, response room.Response) *Assertion {
+	return &Assertion{response: response, t: t}
+}
+
+// Check collects the failed checks, see Err and Errors.
+func Check(response room.Response) *Assertion {
+	return &Assertion{response: response}
+}
+
+// Status checks the status code of the response.
+func (a *Assertion) Status(code int) *Assertion {
+	if a.t != nil {
+		a.t.Helper()
+	}
+
+	if a.response.StatusCode != code {
+		a.fail("status is %d, expected %d", a.response.StatusCode, code)
+	}
+
+	return a
+}
+
+// HeaderEquals checks the first value of the response header key.
+func (a *Assertion) HeaderEquals(key, value string) *Assertion {
+	if a.t != nil {
+		a.t.Helper()
+	}
+
+	if actual := a.response.HeaderValue(key); actual != value {
+		a.fail("header %s is %q, expected %q", key, actual, value)
+	}
+
+	return a
+}
+
+// BodyContains checks that the body contains substr.
+func (a *Assertion) BodyContains(substr string) *Assertion {
+	if a.t != nil {
+		a.t.Helper()
+	}
+
+	if body, err := a.response.Bytes(); err != nil {
+		a.fail("read body: %v", err)
+	} else if !bytes.Contains(body, []byte(substr)) {
+		a.fail("body %q does not contain %q", body, substr)
+	}
+
+	return a
+}
+
+// JSONField checks the value at path, in the syntax of Response.JSONPath. Values are compared once encoded to JSON,
+// so the int 5 equals the JSON number 5 and a struct equals the object it encodes to.
+func (a *Assertion) JSONField(path string, expected any) *Assertion {
+	if a.t != nil {
+		a.t.Helper()
+	}
+
+	actual, err := a.response.JSONPath(path)
+
+	if err != nil {
+		a.fail("json field %s: %v", path, err)
+
+		return a
+	}
+
+	actualJSON, _ := json.Marshal(actual)
+	expectedJSON, err := json.Marshal(expected)
+
+	if err != nil {
+		a.fail("json field %s: encode expected value: %v", path, err)
+	} else if !bytes.Equal(actualJSON, expectedJSON) {
+		a.fail("json field %s is %s, expected %s", path, actualJSON, expectedJSON)
+	}
+
+	return a
+}
+
+// Errors returns the failed checks.
+func (a *Assertion) Errors() []error {
+	return a.errs
+}
+
+// Err joins the failed checks, nil when all of them passed.
+func (a *Assertion) Err() error {
+	return errors.Join(a.errs...)
+}
+
+// String lists the failed checks one per line.
+func (a *Assertion) String() string {
+	lines := make([]string, len(a.errs))
+
+	for i, err := range a.errs {
+		lines[i] = err.Error()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (a *Assertion) fail(format string, args ...any) {
+	err := fmt.Errorf(format, args...)
+	a.errs = append(a.errs, err)
+
+	if a.t != nil {
+		a.t.Helper()
+		a.t.Errorf("%s %s: %v", a.response.Request.Method, a.response.Request.URI.String(), err)
+	}
+}
@@ -0,0 +1,61 @@
+package roomtest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/WEG-Technology/room"
+)
+
+type recordingT struct {
+	testing.TB
+	failures []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...any) {
+	t.failures = append(t.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssert(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":5,"user":{"name":"room","tags":["a"]}}`))
+	}))
+	defer server.Close()
+
+	response, err := room.NewRequest(server.URL).Send()
+	if err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+
+	Assert(t, response).
+		Status(http.StatusOK).
+		HeaderEquals("Content-Type", "application/json").
+		BodyContains(`"name":"room"`).
+		JSONField("id", 5).
+		JSONField("user.tags", []string{"a"}).
+		JSONField("user", struct {
+			Name string   `json:"name"`
+			Tags []string `json:"tags"`
+		}{"room", []string{"a"}})
+
+	check := Check(response).Status(http.StatusCreated).JSONField("id", 6).JSONField("missing", 1).HeaderEquals("Content-Type", "application/json")
+	if errs := check.Errors(); len(errs) != 3 || check.Err() == nil || !strings.Contains(check.String(), "status is 200, expected 201") {
+		t.Errorf("Check() collected %v, expected the status, id and missing field failures", errs)
+	}
+
+	recorder := &recordingT{}
+	Assert(recorder, response).Status(http.StatusNotFound).JSONField("id", 5)
+	if len(recorder.failures) != 1 || !strings.HasPrefix(recorder.failures[0], "GET "+server.URL) {
+		t.Errorf("Assert() reported %v, expected the status failure to fail the test", recorder.failures)
+	}
+
+	if err = Check(response).Status(http.StatusOK).Err(); err != nil {
+		t.Errorf("Check().Err() returned %v, expected nil", err)
+	}
+}
@@ -0,0 +1,135 @@
+package room
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	headerKeyDate      = "Date"
+	headerKeySignature = "X-Signature"
+)
+
+// Signer signs a request once all of its headers are set, body holds the bytes that will be sent, nil when there are none.
+// Signers run on every attempt, so retried requests are signed again.
+type Signer interface {
+	Sign(req *http.Request, body []byte) error
+}
+
+type SignerFunc func(req *http.Request, body []byte) error
+
+func (f SignerFunc) Sign(req *http.Request, body []byte) error {
+	return f(req, body)
+}
+
+// WithSigner signs the request with signer, signers run in the order they are registered.
+func WithSigner(signer Signer) OptionRequest {
+	return func(request *Request) {
+		request.signers = append(request.signers, signer)
+	}
+}
+
+// WithHMACSigner signs the request with NewHMACSigner, hashFn defaults to sha256.New when nil.
+func WithHMACSigner(keyID, secret string, hashFn func() hash.Hash) OptionRequest {
+	return WithSigner(NewHMACSigner(keyID, secret, hashFn))
+}
+
+// CanonicalizeFunc builds the string an HMAC signature is computed over from the request,
+// the lower-cased names of the signed headers, sorted, and the hex encoded hash of the body.
+type CanonicalizeFunc func(req *http.Request, signedHeaders []string, bodyHash string) string
+
+// DefaultCanonicalize joins with new lines the method, the path with its query, a name:value line per signed header
+// and the body hash.
+func DefaultCanonicalize(req *http.Request, signedHeaders []string, bodyHash string) string {
+	lines := []string{req.Method, req.URL.RequestURI()}
+
+	for _, name := range signedHeaders {
+		lines = append(lines, name+":"+strings.TrimSpace(req.Header.Get(name)))
+	}
+
+	return strings.Join(append(lines, bodyHash), "\n")
+}
+
+// HMACSigner sets the Date header and an X-Signature header of the form
+// keyId="id",headers="content-type date",signature="base64 HMAC of the canonical string".
+type HMACSigner struct {
+	keyID        string
+	secret       []byte
+	hashFn       func() hash.Hash
+	headers      []string
+	canonicalize CanonicalizeFunc
+	now          func() time.Time
+}
+
+type OptionHMACSigner func(signer *HMACSigner)
+
+// WithSignedHeaders replaces the headers covered by the signature, Content-Type and Date by default.
+func WithSignedHeaders(names ...string) OptionHMACSigner {
+	return func(signer *HMACSigner) {
+		signer.headers = names
+	}
+}
+
+func WithCanonicalizer(fn CanonicalizeFunc) OptionHMACSigner {
+	return func(signer *HMACSigner) {
+		signer.canonicalize = fn
+	}
+}
+
+func NewHMACSigner(keyID, secret string, hashFn func() hash.Hash, opts ...OptionHMACSigner) *HMACSigner {
+	if hashFn == nil {
+		hashFn = sha256.New
+	}
+
+	signer := &HMACSigner{
+		keyID:        keyID,
+		secret:       []byte(secret),
+		hashFn:       hashFn,
+		headers:      []string{headerKeyContentType, headerKeyDate},
+		canonicalize: DefaultCanonicalize,
+		now:          time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(signer)
+	}
+
+	return signer
+}
+
+func (s *HMACSigner) Sign(req *http.Request, body []byte) error {
+	if s.keyID == "" || len(s.secret) == 0 {
+		return fmt.Errorf("hmac signer needs a key id and a secret")
+	}
+
+	if req.Header.Get(headerKeyDate) == "" {
+		req.Header.Set(headerKeyDate, s.now().UTC().Format(http.TimeFormat))
+	}
+
+	bodyHash := s.hashFn()
+	bodyHash.Write(body)
+
+	signedHeaders := make([]string, 0, len(s.headers))
+
+	for _, name := range s.headers {
+		signedHeaders = append(signedHeaders, strings.ToLower(name))
+	}
+
+	sort.Strings(signedHeaders)
+
+	mac := hmac.New(s.hashFn, s.secret)
+	mac.Write([]byte(s.canonicalize(req, signedHeaders, hex.EncodeToString(bodyHash.Sum(nil)))))
+
+	req.Header.Set(headerKeySignature, fmt.Sprintf(`keyId="%s",headers="%s",signature="%s"`,
+		s.keyID, strings.Join(signedHeaders, " "), base64.StdEncoding.EncodeToString(mac.Sum(nil))))
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package room
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func expectedHMAC(secret, canonical string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(canonical))
+
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestRequest_SendWithHMACSigner(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodyHash := sha256.Sum256(body)
+
+		canonical := strings.Join([]string{
+			r.Method,
+			r.URL.RequestURI(),
+			"content-type:" + r.Header.Get("Content-Type"),
+			"date:" + r.Header.Get("Date"),
+			hex.EncodeToString(bodyHash[:]),
+		}, "\n")
+
+		expected := `keyId="key-1",headers="content-type date",signature="` + expectedHMAC("secret", canonical) + `"`
+		if r.Header.Get("X-Signature") != expected {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	response, err := NewRequest(server.URL+"/orders?page=2", WithMethod(POST), WithBody(JSONBody(map[string]int{"id": 1})), WithHMACSigner("key-1", "secret", nil)).Send()
+	if err != nil || response.StatusCode != http.StatusOK || strings.TrimSpace(string(response.Data)) != `{"id":1}` {
+		t.Errorf("WithHMACSigner() Send() returned (%d, %s, %v), expected a verified signature and the body intact", response.StatusCode, response.Data, err)
+	}
+
+	if response, err = NewRequest(server.URL, WithHMACSigner("key-1", "secret", sha256.New)).Send(); err != nil || response.StatusCode != http.StatusOK {
+		t.Errorf("WithHMACSigner() Send() without body returned (%d, %v), expected a verified signature", response.StatusCode, err)
+	}
+
+	if _, err = NewRequest(server.URL, WithHMACSigner("", "secret", nil)).Send(); err == nil {
+		t.Error("WithHMACSigner() did not reject an empty key id")
+	}
+}
+
+func TestHMACSigner_Options(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost/users", nil)
+	req.Header.Set("X-Tenant", "acme")
+
+	signer := NewHMACSigner("key-1", "secret", nil,
+		WithSignedHeaders("X-Tenant"),
+		WithCanonicalizer(func(req *http.Request, signedHeaders []string, bodyHash string) string {
+			return req.URL.Path + "|" + strings.Join(signedHeaders, ",") + "|" + req.Header.Get("X-Tenant")
+		}))
+	signer.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	if err := signer.Sign(req, nil); err != nil {
+		t.Fatalf("Sign() returned unexpected error: %v", err)
+	}
+
+	expected := `keyId="key-1",headers="x-tenant",signature="` + expectedHMAC("secret", "/users|x-tenant|acme") + `"`
+	if req.Header.Get("X-Signature") != expected {
+		t.Errorf("Sign() set X-Signature %s, expected %s", req.Header.Get("X-Signature"), expected)
+	}
+	if req.Header.Get("Date") != "Tue, 02 Jan 2024 03:04:05 GMT" {
+		t.Errorf("Sign() set Date %s, expected Tue, 02 Jan 2024 03:04:05 GMT", req.Header.Get("Date"))
+	}
+}
+
+func TestRequest_SendWithFailingSigner(t *testing.T) {
+	signErr := errors.New("no key")
+
+	_, err := NewRequest("http://localhost", WithSigner(SignerFunc(func(*http.Request, []byte) error { return signErr }))).Send()
+	if !errors.Is(err, signErr) {
+		t.Errorf("Send() returned %v, expected the signer error", err)
+	}
+}
@@ -0,0 +1,207 @@
+package room
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	sigV4Algorithm   = "AWS4-HMAC-SHA256"
+	sigV4TimeFormat  = "20060102T150405Z"
+	sigV4DateFormat  = "20060102"
+	headerKeyAmzDate = "X-Amz-Date"
+)
+
+// WithAWSSigV4 signs the request with NewAWSSigV4Signer, see WithSigner to pass signer options such as WithSessionToken.
+func WithAWSSigV4(accessKey, secretKey, region, service string) OptionRequest {
+	return WithSigner(NewAWSSigV4Signer(accessKey, secretKey, region, service))
+}
+
+// AWSSigV4Signer signs requests with the AWS Signature Version 4 algorithm, setting the X-Amz-Date and Authorization headers.
+// Requests to s3 also carry the X-Amz-Content-Sha256 header and keep their path encoded once, as S3 requires.
+type AWSSigV4Signer struct {
+	accessKey    string
+	secretKey    string
+	region       string
+	service      string
+	sessionToken string
+	now          func() time.Time
+}
+
+type OptionAWSSigV4Signer func(signer *AWSSigV4Signer)
+
+// WithSessionToken sends the session token of temporary credentials in the X-Amz-Security-Token header.
+func WithSessionToken(token string) OptionAWSSigV4Signer {
+	return func(signer *AWSSigV4Signer) {
+		signer.sessionToken = token
+	}
+}
+
+func NewAWSSigV4Signer(accessKey, secretKey, region, service string, opts ...OptionAWSSigV4Signer) *AWSSigV4Signer {
+	signer := &AWSSigV4Signer{
+		accessKey: accessKey,
+		secretKey: secretKey,
+		region:    region,
+		service:   service,
+		now:       time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(signer)
+	}
+
+	return signer
+}
+
+func (s *AWSSigV4Signer) Sign(req *http.Request, body []byte) error {
+	if s.accessKey == "" || s.secretKey == "" || s.region == "" || s.service == "" {
+		return fmt.Errorf("sigv4 signer needs an access key, a secret key, a region and a service")
+	}
+
+	now := s.now().UTC()
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set(headerKeyAmzDate, now.Format(sigV4TimeFormat))
+
+	if s.service == "s3" {
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+
+	canonicalHeaders, signedHeaders := s.canonicalHeaders(req)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s.canonicalURI(req.URL),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{now.Format(sigV4DateFormat), s.region, s.service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{sigV4Algorithm, now.Format(sigV4TimeFormat), scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := []byte("AWS4" + s.secretKey)
+
+	for _, part := range []string{now.Format(sigV4DateFormat), s.region, s.service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+
+	req.Header.Set(headerKeyAuthorization, fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, s.accessKey, scope, signedHeaders, hex.EncodeToString(hmacSHA256(key, stringToSign))))
+
+	return nil
+}
+
+// canonicalHeaders covers the host, the content type and every x-amz- header.
+func (s *AWSSigV4Signer) canonicalHeaders(req *http.Request) (string, string) {
+	host := req.Host
+
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	headers := map[string]string{"host": host}
+
+	for key, values := range req.Header {
+		name := strings.ToLower(key)
+
+		if name == "content-type" || strings.HasPrefix(name, "x-amz-") {
+			trimmed := make([]string, 0, len(values))
+
+			for _, value := range values {
+				trimmed = append(trimmed, strings.Join(strings.Fields(value), " "))
+			}
+
+			headers[name] = strings.Join(trimmed, ",")
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var canonical strings.Builder
+
+	for _, name := range names {
+		canonical.WriteString(name + ":" + headers[name] + "\n")
+	}
+
+	return canonical.String(), strings.Join(names, ";")
+}
+
+// canonicalURI encodes every path segment, twice for services other than s3 as SigV4 expects.
+func (s *AWSSigV4Signer) canonicalURI(u *url.URL) string {
+	if u.Path == "" {
+		return "/"
+	}
+
+	segments := strings.Split(u.Path, "/")
+
+	for i, segment := range segments {
+		segments[i] = awsURIEncode(segment)
+
+		if s.service != "s3" {
+			segments[i] = awsURIEncode(segments[i])
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func canonicalQuery(query url.Values) string {
+	pairs := make([]string, 0, len(query))
+
+	for key, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, awsURIEncode(key)+"="+awsURIEncode(value))
+		}
+	}
+
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, "&")
+}
+
+// awsURIEncode percent-encodes everything but the unreserved characters of RFC 3986.
+func awsURIEncode(value string) string {
+	var encoded strings.Builder
+
+	for _, b := range []byte(value) {
+		if 'A' <= b && b <= 'Z' || 'a' <= b && b <= 'z' || '0' <= b && b <= '9' || b == '-' || b == '_' || b == '.' || b == '~' {
+			encoded.WriteByte(b)
+		} else {
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+
+	return encoded.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
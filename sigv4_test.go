@@ -0,0 +1,61 @@
+package room
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The expected signatures come from the get-vanilla cases of the AWS SigV4 test suite.
+func TestAWSSigV4Signer_Sign(t *testing.T) {
+	tests := map[string]string{
+		"http://example.amazonaws.com/":                             "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		"http://example.amazonaws.com/?Param2=value2&Param1=value1": "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+	}
+
+	for target, signature := range tests {
+		signer := NewAWSSigV4Signer("AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "us-east-1", "service")
+		signer.now = func() time.Time { return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC) }
+
+		req, _ := http.NewRequest(http.MethodGet, target, nil)
+		if err := signer.Sign(req, nil); err != nil {
+			t.Fatalf("Sign() returned unexpected error: %v", err)
+		}
+
+		expected := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=" + signature
+		if req.Header.Get("Authorization") != expected {
+			t.Errorf("Sign(%s) set Authorization %s, expected %s", target, req.Header.Get("Authorization"), expected)
+		}
+		if req.Header.Get("X-Amz-Date") != "20150830T123600Z" {
+			t.Errorf("Sign(%s) set X-Amz-Date %s, expected 20150830T123600Z", target, req.Header.Get("X-Amz-Date"))
+		}
+	}
+}
+
+func TestRequest_SendWithAWSSigV4(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("Authorization") + "\n" + r.Header.Get("X-Amz-Content-Sha256") + "\n" + r.Header.Get("X-Amz-Security-Token")))
+	}))
+	defer server.Close()
+
+	signer := NewAWSSigV4Signer("AKID", "secret", "eu-west-1", "s3", WithSessionToken("token"))
+
+	response, err := NewRequest(server.URL+"/bucket/key", WithMethod(PUT), WithBody(ReaderBody(strings.NewReader("content"), "text/plain")), WithSigner(signer)).Send()
+	lines := strings.Split(string(response.Data), "\n")
+	if err != nil || len(lines) != 3 {
+		t.Fatalf("WithAWSSigV4() Send() returned (%s, %v)", response.Data, err)
+	}
+
+	if !strings.Contains(lines[0], "SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date;x-amz-security-token,") {
+		t.Errorf("Sign() set Authorization %s, expected the content type, host and x-amz headers to be signed", lines[0])
+	}
+	if lines[1] != sha256Hex([]byte("content")) || lines[2] != "token" {
+		t.Errorf("Sign() set X-Amz-Content-Sha256 %s and X-Amz-Security-Token %s, expected the body hash and token", lines[1], lines[2])
+	}
+
+	if sha256Hex(nil) != "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" {
+		t.Errorf("sha256Hex() of an empty body returned %s", sha256Hex(nil))
+	}
+}
@@ -0,0 +1,163 @@
+package room
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	headerKeyLastEventID   = "Last-Event-ID"
+	headerValueEventStream = "text/event-stream"
+)
+
+// Event is a server-sent event, Event defaults to "message" as in browsers.
+type Event struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+// EventStream reads server-sent events one at a time from a response body:
+//
+//	stream, err := response.SSE()
+//	defer stream.Close()
+//
+//	for stream.Next() {
+//		event := stream.Event()
+//	}
+//
+//	err = stream.Err()
+type EventStream struct {
+	response    Response
+	reader      *bufio.Reader
+	event       Event
+	lastEventID string
+	retry       time.Duration
+	err         error
+	started     bool
+}
+
+// WithLastEventID resumes an event stream after id, pass EventStream.LastEventID when reconnecting.
+func WithLastEventID(id string) OptionRequest {
+	return func(request *Request) {
+		request.lastEventID = id
+	}
+}
+
+// SSE parses the body as a text/event-stream. Send the request WithStream to get the events as they arrive,
+// the stream stops with the error of the request context once it is cancelled.
+func (r Response) SSE() (*EventStream, error) {
+	if err := r.expectMediaType(headerValueEventStream, func(mediaType string) bool { return mediaType == headerValueEventStream }); err != nil {
+		return nil, err
+	}
+
+	reader, err := r.bodyReader()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventStream{response: r, reader: bufio.NewReader(reader)}, nil
+}
+
+// Next reads the next event, it returns false at the end of the stream or on error.
+func (s *EventStream) Next() bool {
+	if s.err != nil {
+		return false
+	}
+
+	var data strings.Builder
+	var event string
+	var hasData bool
+
+	for {
+		line, err := s.reader.ReadString('\n')
+
+		if err != nil {
+			// An event not terminated by an empty line is incomplete and discarded.
+			if !errors.Is(err, io.EOF) {
+				s.err = err
+			} else {
+				s.err = io.EOF
+			}
+
+			return false
+		}
+
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+
+		if !s.started {
+			line, s.started = strings.TrimPrefix(line, "\uFEFF"), true
+		}
+
+		if line == "" {
+			if !hasData {
+				event = ""
+
+				continue
+			}
+
+			if event == "" {
+				event = "message"
+			}
+
+			s.event = Event{ID: s.lastEventID, Event: event, Data: strings.TrimSuffix(data.String(), "\n")}
+
+			return true
+		}
+
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "data":
+			data.WriteString(value + "\n")
+			hasData = true
+		case "event":
+			event = value
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				s.lastEventID = value
+			}
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms >= 0 {
+				s.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
+
+func (s *EventStream) Event() Event {
+	return s.event
+}
+
+// Err returns the error that stopped the stream, nil when it ended normally.
+func (s *EventStream) Err() error {
+	if errors.Is(s.err, io.EOF) {
+		return nil
+	}
+
+	return s.err
+}
+
+// LastEventID returns the last id sent by the server, to send WithLastEventID when reconnecting.
+func (s *EventStream) LastEventID() string {
+	return s.lastEventID
+}
+
+// Retry returns the reconnection delay sent by the server, zero when it sent none.
+func (s *EventStream) Retry() time.Duration {
+	return s.retry
+}
+
+func (s *EventStream) Close() error {
+	return s.response.Close()
+}
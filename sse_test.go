@@ -0,0 +1,88 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestResponse_SSE(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(": comment\nretry: 1500\n\nid: 1\ndata: first\n\n" +
+			"event: update\r\ndata: line one\r\ndata:line two\r\n\r\n" +
+			"id: " + r.Header.Get("Last-Event-ID") + "2\ndata: {}\n\ndata: incomplete"))
+	}))
+	defer server.Close()
+
+	response, err := NewRequest(server.URL, WithStream(), WithLastEventID("resumed-"), WithHeader(NewHeader().Add("X-Trace", "1"))).Send()
+	if err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+
+	stream, err := response.SSE()
+	if err != nil {
+		t.Fatalf("SSE() returned unexpected error: %v", err)
+	}
+	defer stream.Close()
+
+	var events []Event
+	for stream.Next() {
+		events = append(events, stream.Event())
+	}
+
+	expected := []Event{
+		{ID: "1", Event: "message", Data: "first"},
+		{ID: "1", Event: "update", Data: "line one\nline two"},
+		{ID: "resumed-2", Event: "message", Data: "{}"},
+	}
+	if stream.Err() != nil || len(events) != len(expected) {
+		t.Fatalf("SSE() read %v (%v), expected %v", events, stream.Err(), expected)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Errorf("SSE() read event %+v, expected %+v", events[i], expected[i])
+		}
+	}
+
+	if stream.LastEventID() != "resumed-2" || stream.Retry() != 1500*time.Millisecond {
+		t.Errorf("SSE() kept last id %s and retry %v, expected resumed-2 and 1.5s", stream.LastEventID(), stream.Retry())
+	}
+}
+
+func TestResponse_SSECanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: first\n\n"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	response, err := NewRequest(server.URL, WithStream(), WithContext(ctx)).Send()
+	if err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+
+	stream, _ := response.SSE()
+	defer stream.Close()
+
+	if !stream.Next() || stream.Event().Data != "first" {
+		t.Fatalf("SSE() did not read the first event as it arrived")
+	}
+
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	if stream.Next() || !errors.Is(stream.Err(), context.Canceled) {
+		t.Errorf("SSE() stopped with %v, expected context.Canceled", stream.Err())
+	}
+
+	if _, err = newTestResponse("application/json", "{}").SSE(); err == nil {
+		t.Error("SSE() accepted a JSON response")
+	}
+}
@@ -0,0 +1,81 @@
+package room
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WithThrottleClient makes all the requests built by the client wait out the Retry-After of a throttled host,
+// see ThrottleMiddleware.
+func WithThrottleClient() OptionClient {
+	return WithMiddlewareClient(ThrottleMiddleware())
+}
+
+// ThrottleMiddleware holds back every request to a host that answered 429 Too Many Requests, or 503 Service
+// Unavailable, with a Retry-After header until the delay it asked for passed, instead of each request tripping
+// the limit on its own. Both the delay-seconds and HTTP-date forms are understood. The wait respects the request
+// context, Send fails with its error once it is done.
+func ThrottleMiddleware() Middleware {
+	cooldowns := newHostMap(func() *cooldown {
+		return &cooldown{}
+	})
+
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			cooldown := cooldowns.get(req.URL.Host)
+
+			if err := cooldown.wait(req); err != nil {
+				closeReaders(req.Body)
+
+				return nil, err
+			}
+
+			response, err := next(req)
+
+			if delay, ok := retryAfter(response); ok && err == nil {
+				cooldown.extend(time.Now().Add(delay))
+			}
+
+			return response, err
+		}
+	}
+}
+
+// cooldown is the time until which a host asked not to be sent requests.
+type cooldown struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+func (c *cooldown) extend(until time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if until.After(c.until) {
+		c.until = until
+	}
+}
+
+// wait sleeps until the cooldown passed, checking it again since a response may have extended it meanwhile.
+func (c *cooldown) wait(req *http.Request) error {
+	for {
+		c.mu.Lock()
+		delay := time.Until(c.until)
+		c.mu.Unlock()
+
+		if delay <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+
+			return req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
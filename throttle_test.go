@@ -0,0 +1,77 @@
+package room
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_WithThrottleClient(t *testing.T) {
+	var calls atomic.Int32
+	var mu sync.Mutex
+	var throttledAt time.Time
+	var early []time.Duration
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if calls.Add(1) == 1 {
+			throttledAt = time.Now()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		if elapsed := time.Since(throttledAt); elapsed < 900*time.Millisecond {
+			early = append(early, elapsed)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithThrottleClient())
+
+	if response, _ := client.Get("/").Send(); response.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("Send() returned %d, expected the 429 of the server", response.StatusCode)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if response, err := client.Get("/").Send(); err != nil || response.StatusCode != http.StatusOK {
+				t.Errorf("Send() returned (%d, %v), expected 200 after the cooldown", response.StatusCode, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(early) > 0 {
+		t.Errorf("Requests reached the server %v after the 429, expected them held back for the Retry-After delay", early)
+	}
+
+	calls.Store(0)
+	throttle := ThrottleMiddleware()
+	_, _ = NewRequest(server.URL, WithMiddleware(throttle)).Send()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := NewRequest(server.URL, WithMiddleware(throttle), WithContext(ctx)).Send(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Send() returned %v during the cooldown, expected the deadline of the request context", err)
+	}
+}
+
+func TestRetryAfter_FutureHTTPDate(t *testing.T) {
+	response := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {time.Now().Add(2 * time.Second).UTC().Format(http.TimeFormat)}}}
+
+	if delay, ok := retryAfter(response); !ok || delay <= 0 || delay > 2*time.Second {
+		t.Errorf("retryAfter() returned (%s, %t), expected the delay until the HTTP-date", delay, ok)
+	}
+}
@@ -0,0 +1,64 @@
+package room
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+)
+
+// TransportTimeouts bound the phases of a request below the overall context timeout, so slow upstreams fail fast.
+// A zero field keeps the value of the transport, which for the default one is a 30s dial, a 10s TLS handshake,
+// no response header timeout and 90s before idle connections are closed.
+type TransportTimeouts struct {
+	// Dial bounds establishing the TCP connection.
+	Dial time.Duration
+	// TLSHandshake bounds the TLS handshake once connected.
+	TLSHandshake time.Duration
+	// ResponseHeader bounds the wait for the response headers once the request is written.
+	ResponseHeader time.Duration
+	// IdleConn is how long an idle keep-alive connection stays in the pool.
+	IdleConn time.Duration
+}
+
+// WithTransportTimeouts applies cfg to the transport the request is sent with, see WithTransportOverride for injected clients.
+// The dial timeout wraps the dialer in place, so it also bounds WithDialContext and WithUnixSocket when they come first.
+func WithTransportTimeouts(cfg TransportTimeouts) OptionRequest {
+	return func(request *Request) {
+		request.addTransportOption(cfg, cfg.Configure)
+	}
+}
+
+// Configure applies the timeouts to transport, ConfigureTransport(cfg.Configure) sets them for every request on the shared client.
+func (cfg TransportTimeouts) Configure(transport *http.Transport) {
+	if cfg.Dial > 0 {
+		transport.DialContext = dialTimeout(transport.DialContext, cfg.Dial)
+	}
+
+	if cfg.TLSHandshake > 0 {
+		transport.TLSHandshakeTimeout = cfg.TLSHandshake
+	}
+
+	if cfg.ResponseHeader > 0 {
+		transport.ResponseHeaderTimeout = cfg.ResponseHeader
+	}
+
+	if cfg.IdleConn > 0 {
+		transport.IdleConnTimeout = cfg.IdleConn
+	}
+}
+
+func dialTimeout(dial func(ctx context.Context, network, addr string) (net.Conn, error), timeout time.Duration) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	if dial == nil {
+		dialer := &net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}
+
+		return dialer.DialContext
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		return dial(ctx, network, addr)
+	}
+}
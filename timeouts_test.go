@@ -0,0 +1,50 @@
+package room
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequest_SendWithTransportTimeouts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	start := time.Now()
+
+	_, err := NewRequest(server.URL, WithTransportTimeouts(TransportTimeouts{ResponseHeader: 20 * time.Millisecond})).Send()
+	if err == nil || !IsTimeout(err) {
+		t.Errorf("Send() returned %v, expected a response header timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed >= 200*time.Millisecond {
+		t.Errorf("Send() returned after %s, expected to fail before the response", elapsed)
+	}
+}
+
+func TestTransportTimeouts_Configure(t *testing.T) {
+	var deadline time.Time
+
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			deadline, _ = ctx.Deadline()
+
+			return nil, context.DeadlineExceeded
+		},
+	}
+
+	TransportTimeouts{Dial: time.Second, TLSHandshake: 2 * time.Second, IdleConn: 3 * time.Second}.Configure(transport)
+
+	if transport.TLSHandshakeTimeout != 2*time.Second || transport.IdleConnTimeout != 3*time.Second || transport.ResponseHeaderTimeout != 0 {
+		t.Errorf("Configure() set %s, %s and %s, expected 2s, 3s and 0s", transport.TLSHandshakeTimeout, transport.IdleConnTimeout, transport.ResponseHeaderTimeout)
+	}
+
+	_, _ = transport.DialContext(context.Background(), "tcp", "localhost:80")
+	if remaining := time.Until(deadline); remaining <= 0 || remaining > time.Second {
+		t.Errorf("Configure() dialed with %s left, expected the dial bounded to 1s", remaining)
+	}
+}
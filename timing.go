@@ -0,0 +1,92 @@
+package room
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timings break down the last attempt of a request. DNS, Connect and TLSHandshake stay zero on a reused connection.
+// FirstByte, the time to first byte, and Total are measured from the start of the attempt, Total ending once the response
+// headers were received, a body read afterwards is not included.
+type Timings struct {
+	DNS          time.Duration
+	Connect      time.Duration
+	TLSHandshake time.Duration
+	FirstByte    time.Duration
+	Total        time.Duration
+	ReusedConn   bool
+}
+
+// Duration returns the time the round-trip of the last attempt took, until the response headers were received.
+func (r Response) Duration() time.Duration {
+	return r.timings.Total
+}
+
+// Timings returns the phases of the last attempt traced with httptrace, see Timings.
+func (r Response) Timings() Timings {
+	return r.timings
+}
+
+// timingTrace collects Timings from httptrace callbacks, which the transport may call from several goroutines.
+type timingTrace struct {
+	mu                               sync.Mutex
+	start                            time.Time
+	dnsStart, connectStart, tlsStart time.Time
+	timings                          Timings
+}
+
+func newTimingTrace() *timingTrace {
+	return &timingTrace{start: time.Now()}
+}
+
+func (t *timingTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.record(func() { t.dnsStart = time.Now() })
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.record(func() { t.timings.DNS = time.Since(t.dnsStart) })
+		},
+		ConnectStart: func(string, string) {
+			t.record(func() {
+				if t.connectStart.IsZero() {
+					t.connectStart = time.Now()
+				}
+			})
+		},
+		ConnectDone: func(string, string, error) {
+			t.record(func() { t.timings.Connect = time.Since(t.connectStart) })
+		},
+		TLSHandshakeStart: func() {
+			t.record(func() { t.tlsStart = time.Now() })
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.record(func() { t.timings.TLSHandshake = time.Since(t.tlsStart) })
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.record(func() { t.timings.ReusedConn = info.Reused })
+		},
+		GotFirstResponseByte: func() {
+			t.record(func() { t.timings.FirstByte = time.Since(t.start) })
+		},
+	}
+}
+
+func (t *timingTrace) record(fn func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	fn()
+}
+
+// done stops the measure once the round-trip returned.
+func (t *timingTrace) done() Timings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.timings.Total = time.Since(t.start)
+
+	return t.timings
+}
@@ -0,0 +1,48 @@
+package room
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestResponse_Timings(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	defer server.Close()
+
+	request := NewRequest(server.URL, WithClient(server.Client()))
+
+	response, err := request.Send()
+	if err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+
+	timings := response.Timings()
+	if response.Duration() < 10*time.Millisecond || response.Duration() != timings.Total {
+		t.Errorf("Duration() returned %s, expected at least 10ms and %s", response.Duration(), timings.Total)
+	}
+	if timings.Connect <= 0 || timings.TLSHandshake <= 0 {
+		t.Errorf("Timings() returned connect %s and TLS %s, expected both to be measured", timings.Connect, timings.TLSHandshake)
+	}
+	if timings.FirstByte <= 0 || timings.FirstByte > timings.Total {
+		t.Errorf("Timings() returned first byte %s, expected it within %s", timings.FirstByte, timings.Total)
+	}
+	if timings.ReusedConn {
+		t.Error("Timings() reported a reused connection on the first request")
+	}
+
+	response, err = request.Send()
+	if err != nil {
+		t.Fatalf("Send() returned unexpected error: %v", err)
+	}
+	if timings = response.Timings(); !timings.ReusedConn || timings.Connect != 0 || timings.TLSHandshake != 0 {
+		t.Errorf("Timings() returned %+v, expected a reused connection without connect or TLS phases", timings)
+	}
+}
@@ -0,0 +1,69 @@
+package room
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+)
+
+// WithTLSConfig sends the request over a transport using a copy of cfg, see WithTransportOverride for injected clients.
+// The requests given the same cfg share the transport, cfg is copied when the option is created so later changes are ignored.
+func WithTLSConfig(cfg *tls.Config) OptionRequest {
+	key := tlsConfigOption{cfg: cfg}
+	cfg = cfg.Clone()
+
+	return func(request *Request) {
+		request.addTransportOption(key, func(transport *http.Transport) {
+			transport.TLSClientConfig = cfg.Clone()
+		})
+	}
+}
+
+type tlsConfigOption struct {
+	cfg *tls.Config
+}
+
+// WithClientCertificate presents cert to servers asking for a client certificate, as mTLS services do.
+func WithClientCertificate(cert tls.Certificate) OptionRequest {
+	// The chain identifies the certificate, its private key matching the leaf.
+	return withTLS(clientCertificateOption{chain: string(bytes.Join(cert.Certificate, nil))}, func(cfg *tls.Config) {
+		cfg.Certificates = append(cfg.Certificates, cert)
+	})
+}
+
+// WithRootCAs verifies server certificates against pool instead of the system roots.
+func WithRootCAs(pool *x509.CertPool) OptionRequest {
+	return withTLS(rootCAsOption{pool: pool}, func(cfg *tls.Config) {
+		cfg.RootCAs = pool
+	})
+}
+
+// WithInsecureSkipVerify disables the verification of the server certificate chain and host name.
+//
+// SECURITY: the connection is still encrypted but anybody on the network path can impersonate the server
+// and read or alter the traffic, credentials included. Use it against local test servers only,
+// WithRootCAs is the safe way to trust a private CA.
+func WithInsecureSkipVerify() OptionRequest {
+	return withTLS(insecureSkipVerifyOption{}, func(cfg *tls.Config) {
+		cfg.InsecureSkipVerify = true
+	})
+}
+
+type (
+	clientCertificateOption  struct{ chain string }
+	rootCAsOption            struct{ pool *x509.CertPool }
+	insecureSkipVerifyOption struct{}
+)
+
+func withTLS(key any, fn func(cfg *tls.Config)) OptionRequest {
+	return func(request *Request) {
+		request.addTransportOption(key, func(transport *http.Transport) {
+			if transport.TLSClientConfig == nil {
+				transport.TLSClientConfig = &tls.Config{}
+			}
+
+			fn(transport.TLSClientConfig)
+		})
+	}
+}
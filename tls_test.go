@@ -0,0 +1,112 @@
+package room
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequest_SendWithTLSOptions(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("secure"))
+	}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	if _, err := NewRequest(server.URL).Send(); err == nil {
+		t.Error("Send() trusted a certificate signed by an unknown authority")
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	response, err := NewRequest(server.URL, WithRootCAs(pool)).Send()
+	if err != nil || string(response.Data) != "secure" {
+		t.Errorf("WithRootCAs() Send() returned (%s, %v), expected secure", response.Data, err)
+	}
+
+	if response, err = NewRequest(server.URL, WithInsecureSkipVerify()).Send(); err != nil || string(response.Data) != "secure" {
+		t.Errorf("WithInsecureSkipVerify() Send() returned (%s, %v), expected secure", response.Data, err)
+	}
+
+	if response, err = NewRequest(server.URL, WithTLSConfig(&tls.Config{RootCAs: pool})).Send(); err != nil || string(response.Data) != "secure" {
+		t.Errorf("WithTLSConfig() Send() returned (%s, %v), expected secure", response.Data, err)
+	}
+}
+
+func TestRequest_SendWithTLSOptionsOnInjectedClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	transport := &http.Transport{}
+	client := &http.Client{Transport: transport}
+
+	if _, err := NewRequest(server.URL, WithClient(client), WithInsecureSkipVerify()).Send(); !errors.Is(err, ErrInjectedClient) {
+		t.Errorf("Send() returned %v, expected ErrInjectedClient", err)
+	}
+
+	if _, err := NewRequest(server.URL, WithClient(client), WithInsecureSkipVerify(), WithTransportOverride()).Send(); err != nil {
+		t.Errorf("WithTransportOverride() Send() returned %v, expected nil", err)
+	}
+
+	if transport.TLSClientConfig != nil && transport.TLSClientConfig.InsecureSkipVerify {
+		t.Error("WithTransportOverride() modified the transport of the injected client")
+	}
+}
+
+func TestRequest_SendWithClientCertificate(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.Organization[0]))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	if _, err := NewRequest(server.URL, WithInsecureSkipVerify()).Send(); err == nil {
+		t.Error("Send() succeeded without the client certificate required by the server")
+	}
+
+	response, err := NewRequest(server.URL, WithInsecureSkipVerify(), WithClientCertificate(server.TLS.Certificates[0])).Send()
+	if err != nil || string(response.Data) != "Acme Co" {
+		t.Errorf("WithClientCertificate() Send() returned (%s, %v), expected Acme Co", response.Data, err)
+	}
+}
+
+func TestRequest_TransportClientIsReused(t *testing.T) {
+	r := NewRequest("https://localhost", WithInsecureSkipVerify())
+
+	first, _ := r.httpClient()
+	second, _ := r.httpClient()
+	if first.Transport != second.Transport || first.Transport == DefaultClient().Transport {
+		t.Error("httpClient() did not reuse the transport derived for the transport options")
+	}
+
+	other, _ := NewRequest("https://localhost", WithInsecureSkipVerify()).httpClient()
+	dial, _ := NewRequest("https://localhost", WithInsecureSkipVerify(), WithDialContext(nil)).httpClient()
+	if other.Transport != first.Transport || dial.Transport == first.Transport {
+		t.Error("httpClient() did not share the transport across requests with the same options only")
+	}
+}
+
+func TestRequest_SendSharesTransportOptions(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	for i := 0; i < 2; i++ {
+		response, err := NewRequest(server.URL, WithInsecureSkipVerify(), WithTransportTimeouts(TransportTimeouts{ResponseHeader: time.Second})).Send()
+		if err != nil {
+			t.Fatalf("Send() returned unexpected error: %v", err)
+		}
+		if i > 0 && !response.Timings().ReusedConn {
+			t.Error("Send() of a second request with the same transport options did not reuse the connection of the first one")
+		}
+	}
+}
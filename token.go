@@ -0,0 +1,171 @@
+package room
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TokenProvider returns a valid bearer token, refreshing it when it expired.
+// An oauth2.TokenSource is adapted with a TokenProviderFunc returning its AccessToken.
+type TokenProvider interface {
+	Token() (string, error)
+}
+
+type TokenProviderFunc func() (string, error)
+
+func (f TokenProviderFunc) Token() (string, error) {
+	return f()
+}
+
+// TokenRefresher is implemented by providers able to replace a token the server rejected before it expired.
+// TokenMiddleware falls back to Token for providers that do not implement it.
+type TokenRefresher interface {
+	Refresh() (string, error)
+}
+
+// WithTokenProvider sends the request with a bearer token from provider, see TokenMiddleware.
+func WithTokenProvider(provider TokenProvider) OptionRequest {
+	return WithMiddleware(TokenMiddleware(provider))
+}
+
+// TokenMiddleware sets "Authorization: Bearer <token>" on every attempt. On a 401 it refreshes the token once
+// and sends the request again, unless the body cannot be replayed, in which case the 401 is returned.
+// Concurrent 401s of the same token trigger a single refresh, for the requests built with the same middleware or option value.
+func TokenMiddleware(provider TokenProvider) Middleware {
+	refresh := &tokenRefresh{provider: provider}
+
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			token, err := provider.Token()
+
+			if err != nil {
+				closeReaders(req.Body)
+
+				return nil, fmt.Errorf("fetch token: %w", err)
+			}
+
+			req.Header.Set(headerKeyAuthorization, bearer(token))
+
+			response, err := next(req)
+
+			if err != nil || response.StatusCode != http.StatusUnauthorized {
+				return response, err
+			}
+
+			if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+				return response, nil
+			}
+
+			refreshed, err := refresh.replace(token)
+
+			if err != nil || refreshed == token {
+				return response, nil
+			}
+
+			retry := req.Clone(req.Context())
+
+			if req.GetBody != nil {
+				if retry.Body, err = req.GetBody(); err != nil {
+					return response, nil
+				}
+			}
+
+			discardBody(response)
+
+			retry.Header.Set(headerKeyAuthorization, bearer(refreshed))
+
+			return next(retry)
+		}
+	}
+}
+
+// tokenRefresh remembers the last token it replaced, and the token replacing it.
+type tokenRefresh struct {
+	mu        sync.Mutex
+	provider  TokenProvider
+	rejected  string
+	refreshed string
+}
+
+// replace refreshes the rejected token, the 401s waiting for the refresh of the same token getting its result.
+func (r *tokenRefresh) replace(rejected string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.refreshed != "" && r.rejected == rejected {
+		return r.refreshed, nil
+	}
+
+	refreshed, err := refreshToken(r.provider)
+
+	if err != nil {
+		return "", err
+	}
+
+	r.rejected, r.refreshed = rejected, refreshed
+
+	return refreshed, nil
+}
+
+func refreshToken(provider TokenProvider) (string, error) {
+	if refresher, ok := provider.(TokenRefresher); ok {
+		return refresher.Refresh()
+	}
+
+	return provider.Token()
+}
+
+var ErrEmptyToken = errors.New("token provider returned an empty token")
+
+// CachedTokenProvider keeps the token returned by fetch until shortly before its expiry, a zero expiry never expires.
+// It is safe for concurrent use and implements TokenRefresher.
+type CachedTokenProvider struct {
+	mu     sync.Mutex
+	fetch  func() (token string, expiry time.Time, err error)
+	leeway time.Duration
+	token  string
+	expiry time.Time
+	now    func() time.Time
+}
+
+// NewCachedTokenProvider fetches a new token once the current one expires within leeway.
+func NewCachedTokenProvider(fetch func() (token string, expiry time.Time, err error), leeway time.Duration) *CachedTokenProvider {
+	return &CachedTokenProvider{fetch: fetch, leeway: leeway, now: time.Now}
+}
+
+func (p *CachedTokenProvider) Token() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && (p.expiry.IsZero() || p.now().Add(p.leeway).Before(p.expiry)) {
+		return p.token, nil
+	}
+
+	return p.refreshLocked()
+}
+
+func (p *CachedTokenProvider) Refresh() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.refreshLocked()
+}
+
+func (p *CachedTokenProvider) refreshLocked() (string, error) {
+	token, expiry, err := p.fetch()
+
+	if err != nil {
+		return "", err
+	}
+
+	if token == "" {
+		return "", ErrEmptyToken
+	}
+
+	p.token, p.expiry = token, expiry
+
+	return token, nil
+}
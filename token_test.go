@@ -0,0 +1,134 @@
+package room
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRequest_SendWithTokenProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	fetches := 0
+	provider := NewCachedTokenProvider(func() (string, time.Time, error) {
+		fetches++
+		return "token-" + strconv.Itoa(fetches), time.Time{}, nil
+	}, 0)
+
+	response, err := NewRequest(server.URL, WithMethod(POST), WithBody(JSONBody("payload")), WithTokenProvider(provider)).Send()
+	if err != nil || response.StatusCode != http.StatusOK || string(response.Data) != "\"payload\"\n" {
+		t.Errorf("WithTokenProvider() Send() returned (%d, %q, %v), expected a retry with the refreshed token", response.StatusCode, response.Data, err)
+	}
+
+	if response, err = NewRequest(server.URL, WithTokenProvider(provider)).Send(); err != nil || response.StatusCode != http.StatusOK || fetches != 2 {
+		t.Errorf("WithTokenProvider() Send() returned (%d, %v) after %d fetches, expected the cached token", response.StatusCode, err, fetches)
+	}
+}
+
+func TestRequest_SendWithRejectedToken(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	provider := TokenProviderFunc(func() (string, error) { return "static", nil })
+
+	response, err := NewRequest(server.URL, WithTokenProvider(provider)).Send()
+	if err != nil || response.StatusCode != http.StatusUnauthorized || calls != 1 {
+		t.Errorf("Send() returned (%d, %v) after %d calls, expected the 401 without retrying the same token", response.StatusCode, err, calls)
+	}
+
+	errFetch := errors.New("identity provider down")
+	if _, err = NewRequest(server.URL, WithTokenProvider(TokenProviderFunc(func() (string, error) { return "", errFetch }))).Send(); !errors.Is(err, errFetch) {
+		t.Errorf("Send() returned %v, expected the token provider error", err)
+	}
+}
+
+func TestRequest_SendWithConcurrentRejectedTokens(t *testing.T) {
+	const requests = 8
+
+	var arrived atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer token-2" {
+			return
+		}
+
+		// Every request is rejected at once, so their refreshes overlap.
+		if arrived.Add(1) == requests {
+			close(release)
+		}
+
+		select {
+		case <-release:
+		case <-time.After(2 * time.Second):
+		}
+
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	var fetches atomic.Int32
+	provider := NewCachedTokenProvider(func() (string, time.Time, error) {
+		return "token-" + strconv.Itoa(int(fetches.Add(1))), time.Time{}, nil
+	}, 0)
+	opt := WithTokenProvider(provider)
+
+	var wg sync.WaitGroup
+	statuses := make([]int, requests)
+	for i := range statuses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			if response, err := NewRequest(server.URL, opt).Send(); err == nil {
+				statuses[i] = response.StatusCode
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for _, status := range statuses {
+		if status != http.StatusOK || fetches.Load() != 2 {
+			t.Fatalf("Send() returned the statuses %v after %d fetches, expected every request retried with a single refreshed token", statuses, fetches.Load())
+		}
+	}
+}
+
+func TestCachedTokenProvider_Expiry(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fetches := 0
+
+	provider := NewCachedTokenProvider(func() (string, time.Time, error) {
+		fetches++
+		return "token-" + strconv.Itoa(fetches), now.Add(time.Minute), nil
+	}, 10*time.Second)
+	provider.now = func() time.Time { return now }
+
+	first, _ := provider.Token()
+	now = now.Add(45 * time.Second)
+	second, _ := provider.Token()
+	now = now.Add(10 * time.Second)
+	third, _ := provider.Token()
+
+	if first != "token-1" || second != "token-1" || third != "token-2" {
+		t.Errorf("Token() returned %s, %s, %s, expected token-1, token-1, token-2", first, second, third)
+	}
+}
@@ -0,0 +1,91 @@
+// Package tracing starts a span around every attempt of a room request and propagates it with the W3C traceparent header.
+// It depends on no tracing library, an OpenTelemetry trace.Tracer is plugged in with a small adapter implementing Tracer
+// and Span on top of trace.Tracer.Start, span.SetAttributes, span.RecordError and span.SpanContext.
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"github.com/WEG-Technology/room"
+)
+
+const headerKeyTraceParent = "traceparent"
+
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+type Span interface {
+	SetAttribute(key string, value any)
+	RecordError(err error)
+	SpanContext() SpanContext
+	End()
+}
+
+// SpanContext identifies a span across services.
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	Sampled bool
+}
+
+func (c SpanContext) IsValid() bool {
+	return c.TraceID != [16]byte{} && c.SpanID != [8]byte{}
+}
+
+// TraceParent formats the context as a W3C traceparent header value.
+func (c SpanContext) TraceParent() string {
+	flags := "00"
+
+	if c.Sampled {
+		flags = "01"
+	}
+
+	return "00-" + hex.EncodeToString(c.TraceID[:]) + "-" + hex.EncodeToString(c.SpanID[:]) + "-" + flags
+}
+
+// WithTracing traces the request with tracer, see Middleware.
+func WithTracing(tracer Tracer) room.OptionRequest {
+	return room.WithMiddleware(Middleware(tracer))
+}
+
+// Middleware starts a "HTTP <method>" span child of the request context for every attempt. The span carries
+// the http.request.method, server.address and http.response.status_code attributes, records transport errors
+// and 5xx responses, and is sent along in the traceparent header when valid.
+func Middleware(tracer Tracer) room.Middleware {
+	return func(next room.RoundTripperFunc) room.RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			ctx, span := tracer.Start(req.Context(), "HTTP "+req.Method)
+			defer span.End()
+
+			span.SetAttribute("http.request.method", req.Method)
+			span.SetAttribute("server.address", req.URL.Hostname())
+
+			// WithContext would share the headers with the request of the caller, a retry sending it again for instance.
+			req = req.Clone(ctx)
+
+			if spanContext := span.SpanContext(); spanContext.IsValid() {
+				req.Header.Set(headerKeyTraceParent, spanContext.TraceParent())
+			}
+
+			response, err := next(req)
+
+			if err != nil {
+				span.RecordError(err)
+
+				return response, err
+			}
+
+			span.SetAttribute("http.response.status_code", response.StatusCode)
+
+			if response.StatusCode >= 500 {
+				span.RecordError(fmt.Errorf("server responded %s", response.Status))
+			}
+
+			return response, nil
+		}
+	}
+}
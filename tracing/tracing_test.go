@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/WEG-Technology/room"
+)
+
+type recordedSpan struct {
+	name       string
+	attributes map[string]any
+	errors     []error
+	ended      bool
+}
+
+func (s *recordedSpan) SetAttribute(key string, value any) {
+	s.attributes[key] = value
+}
+
+func (s *recordedSpan) RecordError(err error) {
+	s.errors = append(s.errors, err)
+}
+
+func (s *recordedSpan) SpanContext() SpanContext {
+	return SpanContext{TraceID: [16]byte{1, 2, 3}, SpanID: [8]byte{4, 5, 6}, Sampled: true}
+}
+
+func (s *recordedSpan) End() {
+	s.ended = true
+}
+
+type recordingTracer struct {
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &recordedSpan{name: name, attributes: map[string]any{}}
+	t.spans = append(t.spans, span)
+
+	return ctx, span
+}
+
+func TestWithTracing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(r.Header.Get("traceparent")))
+	}))
+	defer server.Close()
+
+	tracer := &recordingTracer{}
+
+	response, err := room.NewRequest(server.URL, WithTracing(tracer)).Send()
+	expected := "00-01020300000000000000000000000000-0405060000000000-01"
+	if err != nil || string(response.Data) != expected {
+		t.Errorf("WithTracing() Send() returned (%s, %v), expected traceparent %s", response.Data, err, expected)
+	}
+
+	if len(tracer.spans) != 1 {
+		t.Fatalf("WithTracing() started %d spans, expected 1", len(tracer.spans))
+	}
+
+	span := tracer.spans[0]
+	if span.name != "HTTP GET" || !span.ended || len(span.errors) != 1 {
+		t.Errorf("WithTracing() recorded span %s ended %v with errors %v, expected an ended HTTP GET with the 503", span.name, span.ended, span.errors)
+	}
+	if span.attributes["http.response.status_code"] != http.StatusServiceUnavailable || span.attributes["server.address"] != "127.0.0.1" {
+		t.Errorf("WithTracing() recorded attributes %v", span.attributes)
+	}
+
+	if _, err = room.NewRequest("http://127.0.0.1:1", WithTracing(tracer)).Send(); err == nil || len(tracer.spans[1].errors) != 1 {
+		t.Errorf("WithTracing() did not record the connection error on the span")
+	}
+}
+
+func TestMiddleware_ClonesRequest(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+
+	var sent *http.Request
+	next := func(r *http.Request) (*http.Response, error) {
+		sent = r
+
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}
+
+	if _, err := Middleware(&recordingTracer{})(next)(req); err != nil {
+		t.Fatalf("Middleware() returned unexpected error: %v", err)
+	}
+
+	if req.Header.Get(headerKeyTraceParent) != "" || sent.Header.Get(headerKeyTraceParent) == "" {
+		t.Errorf("Middleware() set traceparent %q on the request of the caller, expected it on a clone only", req.Header.Get(headerKeyTraceParent))
+	}
+}
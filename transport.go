@@ -0,0 +1,213 @@
+package room
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+var (
+	defaultClientMu sync.RWMutex
+	defaultClient   = newDefaultClient(http.DefaultTransport.(*http.Transport).Clone())
+	derivedMu       sync.Mutex
+)
+
+var ErrInjectedClient = errors.New("transport options need WithTransportOverride to apply to an injected client")
+
+func newDefaultClient(transport *http.Transport) *http.Client {
+	return &http.Client{Transport: transport}
+}
+
+// DefaultClient returns the shared client used by Send when a request has no client of its own.
+func DefaultClient() *http.Client {
+	defaultClientMu.RLock()
+	defer defaultClientMu.RUnlock()
+
+	return defaultClient
+}
+
+// ConfigureTransport applies fn to a copy of the shared transport and swaps it in.
+// Requests already in flight keep using the previous transport, whose idle connections are closed.
+func ConfigureTransport(fn func(transport *http.Transport)) {
+	defaultClientMu.Lock()
+	defer defaultClientMu.Unlock()
+
+	previous := defaultClient.Transport.(*http.Transport)
+
+	transport := previous.Clone()
+	fn(transport)
+
+	defaultClient = newDefaultClient(transport)
+
+	previous.CloseIdleConnections()
+	closePools(previous)
+}
+
+// WithTransport sends the request with rt on a copy of the client Send uses, a MockTransport in tests for instance.
+// Transport options such as WithTLSConfig apply to a clone of rt when it is an *http.Transport.
+func WithTransport(rt http.RoundTripper) OptionRequest {
+	return func(request *Request) {
+		request.roundTripper = rt
+		request.derivedClient = nil
+	}
+}
+
+// WithTransportOverride lets transport options such as WithTLSConfig apply to a copy of the transport of a client
+// injected with WithClient. Without it Send fails with ErrInjectedClient rather than ignoring them.
+func WithTransportOverride() OptionRequest {
+	return func(request *Request) {
+		request.overrideClient = true
+	}
+}
+
+// addTransportOption registers fn to customise the transport derived for the request, see transportClient.
+// key is the comparable configuration fn applies, nil when it has none such as a dial func.
+func (r *Request) addTransportOption(key any, fn func(transport *http.Transport)) {
+	r.transportOpts = append(r.transportOpts, fn)
+	r.transportKey = transportOptionKey{previous: r.transportKey, option: key}
+	r.privateTransport = r.privateTransport || key == nil
+	r.derivedClient = nil
+}
+
+// transportOptionKey chains the keys of the transport options in the order they apply.
+type transportOptionKey struct {
+	previous any
+	option   any
+}
+
+// httpClient returns the injected client or the shared default one, with the request's own client settings layered on a copy.
+// The request context built from contextBuilder is applied on top of the client's own Timeout, whichever expires first wins.
+func (r *Request) httpClient() (*http.Client, error) {
+	client := r.client
+
+	if client == nil {
+		client = DefaultClient()
+	}
+
+	if len(r.transportOpts) > 0 {
+		var err error
+
+		if client, err = r.transportClient(client); err != nil {
+			return nil, err
+		}
+	} else if r.pool != nil {
+		var err error
+
+		if client, err = r.pooledClient(client); err != nil {
+			return nil, err
+		}
+	} else if r.roundTripper != nil {
+		withRoundTripper := *client
+		withRoundTripper.Transport = r.roundTripper
+		client = &withRoundTripper
+	}
+
+	if r.checkRedirect == nil && r.jar == nil {
+		return client, nil
+	}
+
+	derived := *client
+
+	if r.checkRedirect != nil {
+		derived.CheckRedirect = chainCheckRedirect(r.checkRedirect, client.CheckRedirect)
+	}
+
+	if r.jar != nil {
+		derived.Jar = r.jar
+	}
+
+	return &derived, nil
+}
+
+// transportClient applies the transport options to a clone of the transport of base. The clone is shared with the requests
+// applying the same options on the same transport, like WithConnectionPool, until it is replaced by ConfigureTransport.
+// An option without a comparable configuration, WithDialContext, keeps the clone on the request instead, so only
+// its retries and repeated sends share its connections, until base changes.
+func (r *Request) transportClient(base *http.Client) (*http.Client, error) {
+	transport, err := r.baseTransport(base)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !r.privateTransport {
+		var cfg PoolConfig
+
+		if r.pool != nil {
+			cfg = *r.pool
+		}
+
+		derived := *base
+		derived.Transport = sharedTransport(poolKey{base: transport, cfg: cfg, options: r.transportKey}, r.transportOpts)
+
+		return &derived, nil
+	}
+
+	derivedMu.Lock()
+	defer derivedMu.Unlock()
+
+	if r.derivedClient != nil && r.derivedFrom == base {
+		return r.derivedClient, nil
+	}
+
+	transport = transport.Clone()
+
+	for _, fn := range r.transportOpts {
+		fn(transport)
+	}
+
+	if r.pool != nil {
+		r.pool.Configure(transport)
+	}
+
+	if r.derivedClient != nil {
+		r.derivedClient.CloseIdleConnections()
+	}
+
+	derived := *base
+	derived.Transport = transport
+
+	r.derivedClient, r.derivedFrom = &derived, base
+
+	return r.derivedClient, nil
+}
+
+// pooledClient sends with the transport shared by the requests sized by the same PoolConfig on top of base, see WithConnectionPool.
+func (r *Request) pooledClient(base *http.Client) (*http.Client, error) {
+	transport, err := r.baseTransport(base)
+
+	if err != nil {
+		return nil, err
+	}
+
+	derived := *base
+	derived.Transport = pooledTransport(transport, *r.pool)
+
+	return &derived, nil
+}
+
+// baseTransport returns the transport the transport options of the request apply to.
+func (r *Request) baseTransport(base *http.Client) (*http.Transport, error) {
+	if r.client != nil && r.roundTripper == nil && !r.overrideClient {
+		return nil, ErrInjectedClient
+	}
+
+	roundTripper := base.Transport
+
+	if r.roundTripper != nil {
+		roundTripper = r.roundTripper
+	}
+
+	if roundTripper == nil {
+		roundTripper = http.DefaultTransport
+	}
+
+	transport, ok := roundTripper.(*http.Transport)
+
+	if !ok {
+		return nil, fmt.Errorf("transport options cannot configure a transport of type %T", roundTripper)
+	}
+
+	return transport, nil
+}
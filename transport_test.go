@@ -0,0 +1,37 @@
+package room
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDefaultClient_Shared(t *testing.T) {
+	if DefaultClient() != DefaultClient() {
+		t.Error("DefaultClient() returned a different client on each call")
+	}
+}
+
+func TestConfigureTransport(t *testing.T) {
+	previous := DefaultClient()
+	defer func() {
+		defaultClientMu.Lock()
+		defaultClient = previous
+		defaultClientMu.Unlock()
+	}()
+
+	ConfigureTransport(func(transport *http.Transport) {
+		transport.IdleConnTimeout = time.Second
+	})
+
+	transport, ok := DefaultClient().Transport.(*http.Transport)
+	if !ok {
+		t.Fatal("ConfigureTransport() did not keep an *http.Transport")
+	}
+	if transport.IdleConnTimeout != time.Second {
+		t.Errorf("ConfigureTransport() IdleConnTimeout is %v, expected %v", transport.IdleConnTimeout, time.Second)
+	}
+	if previous.Transport.(*http.Transport).IdleConnTimeout == time.Second {
+		t.Error("ConfigureTransport() mutated the previous transport")
+	}
+}
@@ -1,9 +1,15 @@
 package room
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
 	"strings"
 )
 
+var pathParamPattern = regexp.MustCompile(`\{([^{}/]+)\}`)
+
 type URI struct {
 	scheme    string
 	authority string
@@ -37,6 +43,39 @@ func (u URI) Scheme() string {
 	return u.scheme
 }
 
+var ErrInvalidURL = errors.New("invalid URL")
+
+// ParseURI validates fullUrl before splitting it like NewURI does. A missing scheme defaults to http,
+// any scheme other than http and https, a missing host, spaces and control characters are rejected with ErrInvalidURL.
+func ParseURI(fullUrl string) (URI, error) {
+	if strings.Contains(fullUrl, " ") {
+		return URI{}, fmt.Errorf("%w %q: contains a space, escape it first", ErrInvalidURL, fullUrl)
+	}
+
+	candidate := fullUrl
+
+	if !strings.Contains(candidate, "://") {
+		candidate = "http://" + candidate
+	}
+
+	u, err := url.Parse(candidate)
+
+	if err != nil {
+		return URI{}, fmt.Errorf("%w: %w", ErrInvalidURL, err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return URI{}, fmt.Errorf("%w %q: unsupported scheme %q", ErrInvalidURL, fullUrl, u.Scheme)
+	}
+
+	if u.Host == "" {
+		return URI{}, fmt.Errorf("%w %q: missing host", ErrInvalidURL, fullUrl)
+	}
+
+	return NewURI(fullUrl), nil
+}
+
+// NewURI splits fullUrl without validating it, see ParseURI.
 func NewURI(fullUrl string) URI {
 	uri := URI{}
 
@@ -72,3 +111,82 @@ func NewURI(fullUrl string) URI {
 
 	return uri
 }
+
+// expandPath replaces {name} placeholders with their path-escaped params.
+// Placeholders without a param are reported instead of being sent literally.
+func expandPath(path string, params map[string]string) (string, error) {
+	var missing []string
+
+	expanded := pathParamPattern.ReplaceAllStringFunc(path, func(placeholder string) string {
+		name := placeholder[1 : len(placeholder)-1]
+
+		if value, ok := params[name]; ok {
+			return url.PathEscape(value)
+		}
+
+		missing = append(missing, name)
+
+		return placeholder
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("unresolved path params %s in %q", strings.Join(missing, ", "), path)
+	}
+
+	return expanded, nil
+}
+
+// joinURL resolves path against baseUrl the way net/url does, except that the last segment of the base path is kept:
+// "https://api.example.com/v1" and "users" give "https://api.example.com/v1/users". An absolute path is returned untouched
+// and query strings of both sides are kept.
+func joinURL(baseUrl, path string) string {
+	if ref, err := url.Parse(path); err == nil && ref.Scheme != "" && ref.Host != "" {
+		return path
+	}
+
+	if !strings.Contains(baseUrl, "://") {
+		baseUrl = "http://" + baseUrl
+	}
+
+	base, err := url.Parse(baseUrl)
+	// "./" keeps a first segment holding a colon from being read as a scheme.
+	ref, refErr := url.Parse("./" + strings.TrimPrefix(path, "/"))
+
+	if err != nil || refErr != nil {
+		return strings.TrimSuffix(baseUrl, "/") + "/" + strings.TrimPrefix(path, "/")
+	}
+
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+
+		if base.RawPath != "" {
+			base.RawPath += "/"
+		}
+	}
+
+	resolved := base.ResolveReference(ref)
+	resolved.RawQuery = joinQuery(base.RawQuery, ref.RawQuery)
+
+	return resolved.String()
+}
+
+// appendQuery adds an encoded query to a URL that may already carry one.
+func appendQuery(fullUrl, query string) string {
+	if before, existing, found := strings.Cut(fullUrl, "?"); found {
+		return before + "?" + joinQuery(existing, query)
+	}
+
+	return fullUrl + "?" + query
+}
+
+func joinQuery(queries ...string) string {
+	var parts []string
+
+	for _, query := range queries {
+		if query != "" {
+			parts = append(parts, query)
+		}
+	}
+
+	return strings.Join(parts, "&")
+}
@@ -0,0 +1,105 @@
+package room
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExpandPath(t *testing.T) {
+	params := map[string]string{"id": "42", "orderId": "a b/c"}
+
+	result, err := expandPath("http://localhost/users/{id}/orders/{orderId}", params)
+	expected := "http://localhost/users/42/orders/a%20b%2Fc"
+	if err != nil || result != expected {
+		t.Errorf("expandPath() returned (%s, %v), expected (%s, nil)", result, err, expected)
+	}
+
+	if _, err = expandPath("http://localhost/users/{id}/items/{itemId}", params); err == nil {
+		t.Error("expandPath() did not return an error for an unresolved placeholder")
+	}
+}
+
+func TestRequest_SendWithUnresolvedPathParam(t *testing.T) {
+	_, err := NewRequest("http://localhost/users/{id}", WithPathParams(map[string]string{"other": "1"})).Send()
+	if err == nil {
+		t.Error("Send() did not return an error for an unresolved placeholder")
+	}
+
+	r := NewRequest("http://localhost/users/{id}").SetPathParam("id", "7")
+	if _, err = r.request(r.context().Ctx); err != nil || r.URI.Path() != "/users/7" {
+		t.Errorf("SetPathParam() built path %s (%v), expected /users/7", r.URI.Path(), err)
+	}
+}
+
+func TestJoinURL(t *testing.T) {
+	tests := []struct {
+		base, path, expected string
+	}{
+		{"https://api.example.com/v1", "users", "https://api.example.com/v1/users"},
+		{"https://api.example.com/v1/", "/users", "https://api.example.com/v1/users"},
+		{"https://api.example.com", "users/", "https://api.example.com/users/"},
+		{"https://api.example.com/v1", "", "https://api.example.com/v1/"},
+		{"https://api.example.com/v1?key=1", "users", "https://api.example.com/v1/users?key=1"},
+		{"https://api.example.com/v1", "users?page=2", "https://api.example.com/v1/users?page=2"},
+		{"https://api.example.com/v1?key=1", "users?page=2", "https://api.example.com/v1/users?key=1&page=2"},
+		{"https://api.example.com/v1", "users/a%2Fb", "https://api.example.com/v1/users/a%2Fb"},
+		{"https://api.example.com/v1", "users:batch", "https://api.example.com/v1/users:batch"},
+		{"localhost:8080/v1", "users", "http://localhost:8080/v1/users"},
+		{"https://api.example.com/v1", "https://other.example.com/users", "https://other.example.com/users"},
+	}
+
+	for _, test := range tests {
+		if result := joinURL(test.base, test.path); result != test.expected {
+			t.Errorf("joinURL(%q, %q) returned %s, expected %s", test.base, test.path, result, test.expected)
+		}
+	}
+}
+
+func TestRequest_SetBaseUrl(t *testing.T) {
+	r := NewRequest("users/{id}", WithQuery(NewMapQuery(map[string]any{"page": "2"}))).
+		SetPathParam("id", "7").
+		SetBaseUrl("https://api.example.com/v1?key=1").
+		SetBaseUrl("https://api.example.com/v1?key=1")
+
+	expected := "https://api.example.com/v1/users/7?key=1&page=2"
+	if _, err := r.request(r.context().Ctx); err != nil || r.URI.String() != expected {
+		t.Errorf("SetBaseUrl() built %s (%v), expected %s", r.URI.String(), err, expected)
+	}
+}
+
+func TestParseURI(t *testing.T) {
+	valid := map[string]string{
+		"https://api.example.com/v1/users?page=2": "https://api.example.com/v1/users?page=2",
+		"localhost:8080/users":                    "http://localhost:8080/users",
+	}
+
+	for fullUrl, expected := range valid {
+		if uri, err := ParseURI(fullUrl); err != nil || uri.String() != expected {
+			t.Errorf("ParseURI(%q) returned (%s, %v), expected (%s, nil)", fullUrl, uri.String(), err, expected)
+		}
+	}
+
+	invalid := []string{
+		"https://api.example.com/my users",
+		"ftp://files.example.com/report",
+		"https://api.example.com/users\x7f",
+		"https://api.example.com/users\n",
+		"https:///users",
+	}
+
+	for _, fullUrl := range invalid {
+		if _, err := ParseURI(fullUrl); !errors.Is(err, ErrInvalidURL) {
+			t.Errorf("ParseURI(%q) returned %v, expected ErrInvalidURL", fullUrl, err)
+		}
+	}
+}
+
+func TestRequest_SendWithInvalidURL(t *testing.T) {
+	response, err := NewRequest("https://api.example.com/my users").Send()
+	if !errors.Is(err, ErrInvalidURL) {
+		t.Errorf("Send() returned %v, expected ErrInvalidURL", err)
+	}
+	if response.StatusCode != 0 || len(response.Data) == 0 {
+		t.Errorf("Send() returned status %d and data %s, expected an error response", response.StatusCode, response.Data)
+	}
+}
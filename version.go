@@ -0,0 +1,6 @@
+package room
+
+// Version of the room package, sent in DefaultUserAgent.
+const Version = "0.1.0"
+
+const DefaultUserAgent = "room/" + Version